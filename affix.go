@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefix returns err with prefix prepended to the message of its outermost
+// layer, followed by ": ", as WithMessage would, but without adding a
+// layer to err's chain: the message of Prefix(Wrap(io.EOF, "read"), "retry
+// 2") is "retry 2: read: EOF", and its chain has the same depth and
+// messages as joined by Messages. If err is nil, Prefix returns nil.
+func Prefix(err error, prefix string) error {
+	if err == nil {
+		return nil
+	}
+	return affix(err, prefix+": ", "")
+}
+
+// Suffix returns err with suffix appended, in parentheses, to the message
+// of its outermost layer, without adding a layer to err's chain: the
+// message of Suffix(Wrap(io.EOF, "read"), "offset 42") is
+// "read (offset 42): EOF". If err is nil, Suffix returns nil.
+func Suffix(err error, suffix string) error {
+	if err == nil {
+		return nil
+	}
+	return affix(err, "", " ("+suffix+")")
+}
+
+// affix returns err with the message of its outermost layer between prefix
+// and suffix.
+func affix(err error, prefix, suffix string) error {
+	if w, ok := err.(*withAffixes); ok {
+		prefix, suffix = prefix+w.prefix, w.suffix+suffix
+		err = w.error
+	}
+	msg := err.Error()
+	head := Messages(err)[0]
+	if !strings.HasPrefix(msg, head) {
+		head = msg
+	}
+	return &withAffixes{
+		error:  err,
+		prefix: prefix,
+		suffix: suffix,
+		msg:    prefix + head + suffix + msg[len(head):],
+	}
+}
+
+// withAffixes alters the message of the outermost layer of its cause.
+type withAffixes struct {
+	error
+	prefix, suffix string
+	msg            string // the result of Error
+}
+
+func (w *withAffixes) Error() string { return w.msg }
+
+func (w *withAffixes) Cause() error { return w.error }
+
+func (w *withAffixes) Unwrap() error { return w.error }
+
+func (w *withAffixes) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withAffixes) own() {}
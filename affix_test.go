@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestPrefixSuffix(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	tests := []struct {
+		err      error
+		want     string
+		messages []string
+	}{
+		{Prefix(err, "retry 2"), "retry 2: read: EOF", []string{"retry 2: read", "EOF"}},
+		{Suffix(err, "offset 42"), "read (offset 42): EOF", []string{"read (offset 42)", "EOF"}},
+		{Prefix(Suffix(err, "offset 42"), "retry 2"), "retry 2: read (offset 42): EOF", []string{"retry 2: read (offset 42)", "EOF"}},
+		{Prefix(WithFields(Prefix(err, "a"), Label("k", 1)), "b"), "b: a: read: EOF", []string{"b: a: read", "EOF"}},
+		{Suffix(io.EOF, "end"), "EOF (end)", []string{"EOF (end)"}},
+		{Prefix(fmt.Errorf("open: %w", io.EOF), "load"), "load: open: EOF", []string{"load: open", "EOF"}},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := Messages(tt.err); !reflect.DeepEqual(got, tt.messages) {
+			t.Errorf("test %d: Messages: got %q, want %q", i+1, got, tt.messages)
+		}
+		if !Is(tt.err, io.EOF) {
+			t.Errorf("test %d: %v does not wrap io.EOF", i+1, tt.err)
+		}
+	}
+	if got, want := fmt.Sprintf("%+v", Prefix(err, "retry 2")), "retry 2: "+fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if Prefix(nil, "a") != nil || Suffix(nil, "a") != nil {
+		t.Errorf("Prefix, Suffix: got non-nil error for nil")
+	}
+}
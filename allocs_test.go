@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// TestAllocs enforces the maximum number of allocations made by the
+// functions whose documentation states it, and by formatting, so that a
+// change making them more expensive is noticed.
+func TestAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	stacked := New("stacked")
+	wrapped := Wrap(io.EOF, "wrapped")
+	classified := Wrap(WithKind(WithCode(io.EOF, "E1"), notFound), "classified")
+	var msg string
+	tests := []struct {
+		name string
+		max  float64
+		fn   func()
+	}{
+		{"New", 2, func() { GlobalE = New("new") }},
+		{"Wrap", 2, func() { GlobalE = Wrap(io.EOF, "wrap") }},
+		{"Wrap stacked", 1, func() { GlobalE = Wrap(stacked, "wrap") }},
+		{"NewNoStack", 2, func() { GlobalE = NewNoStack("new") }},
+		{"WrapNoStack", 1, func() { GlobalE = WrapNoStack(io.EOF, "wrap") }},
+		{"Build New", 2, func() { GlobalE = Build().Msg("new").New() }},
+		{"Build Wrap", 2, func() { GlobalE = Build().Msg("wrap").Wrap(io.EOF) }},
+		{"Build Wrap Code", 3, func() { GlobalE = Build().Msg("wrap").Code("E1").Wrap(io.EOF) }},
+		{"New Error", 0, func() { msg = stacked.Error() }},
+		{"Wrap Error", 0, func() { msg = wrapped.Error() }},
+		{"HasStack", 0, func() { GlobalE = HasStack(wrapped) }},
+		{"Cause", 0, func() { GlobalE = Cause(wrapped) }},
+		{"IsCode", 0, func() { GlobalE = IsCode(classified, "E1") }},
+		{"KindOf", 0, func() { msg = string(KindOf(classified)) }},
+		{"%v", 0, func() { fmt.Fprintf(ioutil.Discard, "%v", wrapped) }},
+		{"%+v", 2, func() { fmt.Fprintf(ioutil.Discard, "%+v", wrapped) }},
+	}
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(100, tt.fn); got > tt.max {
+			t.Errorf("%s: got %v allocs, want at most %v", tt.name, got, tt.max)
+		}
+	}
+	GlobalE = msg
+}
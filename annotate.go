@@ -0,0 +1,32 @@
+package errors
+
+import "fmt"
+
+// Annotate wraps *err with the supplied message, as Wrap does, if *err is
+// not nil. It is meant to be deferred with the address of a named result,
+// to annotate every error a function returns at once:
+//
+//	func (db *DB) Close() (err error) {
+//		defer errors.Annotate(&err, "closing database")
+//		...
+//	}
+func Annotate(err *error, message string) {
+	if *err != nil {
+		*err = wrap(*err, intern(message), nil)
+	}
+}
+
+// Annotatef wraps *err with the format specifier, as Wrapf does, if *err is
+// not nil; see Annotate. Like those of any deferred call, the arguments are
+// evaluated when the defer statement is executed, not when the function
+// returns.
+func Annotatef(err *error, format string, args ...interface{}) {
+	if *err == nil {
+		return
+	}
+	if loadConfig().deferFormatting {
+		*err = wrap(*err, "", &lazyMessage{format: format, args: args})
+		return
+	}
+	*err = wrap(*err, intern(fmt.Sprintf(format, args...)), nil)
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func annotated(err error) (rerr error) {
+	defer Annotate(&rerr, "annotated")
+	return err
+}
+
+func annotatedf(err error, n int) (rerr error) {
+	defer Annotatef(&rerr, "annotated %d", n)
+	return err
+}
+
+func TestAnnotate(t *testing.T) {
+	if err := annotated(nil); err != nil {
+		t.Errorf("Annotate(nil): got %v, want nil", err)
+	}
+	err := annotated(io.EOF)
+	if got, want := err.Error(), "annotated: EOF"; got != want {
+		t.Errorf("Annotate: got %q, want %q", got, want)
+	}
+	testFormatRegexp(t, 0, err, "%+v", "annotated: EOF\n"+
+		"github.com/pkg/errors.annotated\n"+
+		"\t.+/annotate_test.go:\\d+\n"+ // line 10, or 11 under -race
+		"github.com/pkg/errors.TestAnnotate\n"+
+		"\t.+/annotate_test.go:22")
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(%v, io.EOF): got false, want true", err)
+	}
+
+	if err := annotatedf(nil, 1); err != nil {
+		t.Errorf("Annotatef(nil): got %v, want nil", err)
+	}
+	if got, want := annotatedf(io.EOF, 2).Error(), "annotated 2: EOF"; got != want {
+		t.Errorf("Annotatef: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+// AsType finds the first error in err's chain that matches the type T, as
+// As does, and returns it together with true if there is one, or the zero
+// value of T and false otherwise. It saves declaring a variable to pass to
+// As:
+//
+//	if pe, ok := errors.AsType[*fs.PathError](err); ok {
+//		log.Printf("failed to %s %s", pe.Op, pe.Path)
+//	}
+//
+// As with As, AsType panics if T is neither an interface type nor a type
+// implementing error.
+func AsType[T any](err error) (T, bool) {
+	var target T
+	ok := As(err, &target)
+	return target, ok
+}
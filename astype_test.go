@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	"io"
+	"io/fs"
+	"testing"
+)
+
+func TestAsType(t *testing.T) {
+	pe := &fs.PathError{Op: "open", Path: "a.txt", Err: fs.ErrNotExist}
+	err := Wrap(WithMessage(pe, "config"), "load")
+	if got, ok := AsType[*fs.PathError](err); !ok || got != pe {
+		t.Errorf("AsType[*fs.PathError]: got %v, %t", got, ok)
+	}
+	if got, ok := AsType[interface{ Timeout() bool }](err); !ok || got != pe {
+		t.Errorf("AsType[timeout]: got %v, %t", got, ok)
+	}
+	if got, ok := AsType[*fs.PathError](Wrap(io.EOF, "read")); ok || got != nil {
+		t.Errorf("AsType[*fs.PathError]: got %v, %t for an error without one", got, ok)
+	}
+	if _, ok := AsType[*fs.PathError](nil); ok {
+		t.Errorf("AsType[*fs.PathError](nil): got true")
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+// WrapAll returns a slice holding each error of errs annotated as by Wrap
+// with the supplied message. The stack trace is captured once, at the
+// point WrapAll is called, and shared by all the errors that had none,
+// which makes annotating many errors identically much cheaper than calling
+// Wrap for each of them. nil errors are left nil.
+func WrapAll(errs []error, message string) []error {
+	message = intern(message)
+	return wrapBatch(errs, func(int) string { return message })
+}
+
+// WrapEach is like WrapAll, but annotates the error at index i of errs with
+// message(i). message is not called for nil errors.
+func WrapEach(errs []error, message func(i int) string) []error {
+	return wrapBatch(errs, func(i int) string { return intern(message(i)) })
+}
+
+// wrapBatch implements WrapAll and WrapEach, recording the stack trace of
+// the caller of the function calling it when first needed.
+func wrapBatch(errs []error, message func(i int) string) []error {
+	if errs == nil {
+		return nil
+	}
+	out := make([]error, len(errs))
+	capture := captureEnabled()
+	var st *withStack // the first stack trace recorded, shared by the others
+	for i, err := range errs {
+		switch {
+		case err == nil:
+		case !capture || hasStack(err):
+			out[i] = &withMessage{cause: err, msg: message(i)}
+		default:
+			w := &wrapped{}
+			if st == nil {
+				w.withStack = withStack{err, callers(1), currentGoroutine()}
+				st = &w.withStack
+			} else {
+				w.withStack = withStack{err, st.stack, st.goid}
+			}
+			w.withMessage.cause = &w.withStack
+			w.withMessage.msg = message(i)
+			out[i] = &w.withMessage
+		}
+	}
+	return out
+}
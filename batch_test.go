@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestWrapAll(t *testing.T) {
+	stacked := New("stacked")
+	errs := WrapAll([]error{io.EOF, nil, io.ErrUnexpectedEOF, stacked}, "item")
+	want := []string{"item: EOF", "", "item: unexpected EOF", "item: stacked"}
+	if len(errs) != len(want) {
+		t.Fatalf("WrapAll: got %d errors, want %d", len(errs), len(want))
+	}
+	for i, err := range errs {
+		if err == nil {
+			if want[i] != "" {
+				t.Errorf("error %d: got nil, want %q", i, want[i])
+			}
+			continue
+		}
+		if got := err.Error(); got != want[i] {
+			t.Errorf("error %d: got %q, want %q", i, got, want[i])
+		}
+	}
+	st0, _ := stackTraceOf(errs[0])
+	st2, _ := stackTraceOf(errs[2])
+	if len(st0) == 0 || st0[0].Name() != "github.com/pkg/errors.TestWrapAll" {
+		t.Errorf("error 0: got stack trace %v, want one recorded in TestWrapAll", st0)
+	}
+	if !reflect.DeepEqual(st0, st2) {
+		t.Errorf("errors 0 and 2: got different stack traces %v and %v", st0, st2)
+	}
+	got, _ := stackTraceOf(errs[3])
+	if want, _ := stackTraceOf(stacked); !reflect.DeepEqual(got, want) {
+		t.Errorf("error 3: got stack trace %v, want that of its cause %v", got, want)
+	}
+	if !Is(errs[2], io.ErrUnexpectedEOF) {
+		t.Errorf("Is(%v, io.ErrUnexpectedEOF): got false, want true", errs[2])
+	}
+	if WrapAll(nil, "item") != nil {
+		t.Errorf("WrapAll(nil): got non-nil slice")
+	}
+}
+
+func TestWrapEach(t *testing.T) {
+	var calls []int
+	errs := WrapEach([]error{io.EOF, nil, io.EOF}, func(i int) string {
+		calls = append(calls, i)
+		return fmt.Sprintf("item %d", i)
+	})
+	if got, want := calls, []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("message called with %v, want %v", got, want)
+	}
+	if got, want := errs[2].Error(), "item 2: EOF"; got != want {
+		t.Errorf("error 2: got %q, want %q", got, want)
+	}
+	if errs[1] != nil {
+		t.Errorf("error 1: got %v, want nil", errs[1])
+	}
+}
@@ -4,6 +4,7 @@ package errors
 
 import (
 	"fmt"
+	"io"
 	"testing"
 
 	stderrors "errors"
@@ -108,3 +109,157 @@ func BenchmarkStackFormatting(b *testing.B) {
 	}
 	GlobalE = stackStr
 }
+
+func BenchmarkWrap(b *testing.B) {
+	cause := New("cause")
+	runs := []struct {
+		name string
+		wrap func(err error) error
+	}{
+		{"Wrap", func(err error) error { return Wrap(err, "wrapped") }},
+		{"Wrapf", func(err error) error { return Wrapf(err, "wrapped %d", 1) }},
+		// fmt.Errorf is how Wrap used to build its message.
+		{"fmt.Errorf", func(err error) error { return formatted{fmt.Errorf("%s: %w", "wrapped", ensureStack(err))} }},
+	}
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = r.wrap(cause)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkStackCapture(b *testing.B) {
+	defer SetStackCapture(true)
+	for _, enabled := range []bool{true, false} {
+		SetStackCapture(enabled)
+		name := "enabled"
+		if !enabled {
+			name = "disabled"
+		}
+		b.Run("New-"+name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = yesErrors(0, 10)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+		b.Run("Wrap-"+name, func(b *testing.B) {
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = Wrap(io.EOF, "wrapped")
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
+
+func BenchmarkCallers(b *testing.B) {
+	defer SetSingleFrameCapture(false)
+	for _, single := range []bool{false, true} {
+		SetSingleFrameCapture(single)
+		name := "full"
+		if single {
+			name = "single"
+		}
+		b.Run(name, func(b *testing.B) {
+			var st stack
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				st = callers(0)
+			}
+			b.StopTimer()
+			GlobalE = st
+		})
+	}
+}
+
+func BenchmarkHasStack(b *testing.B) {
+	err := Wrap(Wrap(New("innermost"), "middle"), "outermost")
+	b.Run("HasStack", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GlobalE = HasStack(err)
+		}
+	})
+	b.Run("As", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var st stackTracer
+			GlobalE = As(err, &st)
+		}
+	})
+}
+
+func BenchmarkPooled(b *testing.B) {
+	b.Run("Wrap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GlobalE = Wrap(io.EOF, "read")
+		}
+	})
+	b.Run("GetWrap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			err := GetWrap(io.EOF, "read")
+			GlobalE = err
+			Release(err)
+		}
+	})
+}
+
+func BenchmarkWithMessageChain(b *testing.B) {
+	for _, r := range []struct {
+		name string
+		fn   func(err error, message string) error
+	}{
+		{"WithMessage", WithMessage},
+		{"fmt.Errorf", func(err error, message string) error { return fmt.Errorf("%s: %w", message, err) }},
+	} {
+		b.Run(r.name, func(b *testing.B) {
+			var msg string
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := io.EOF
+				for j := 0; j < 10; j++ {
+					err = r.fn(err, "a message of some length")
+				}
+				msg = err.Error()
+			}
+			b.StopTimer()
+			GlobalE = msg
+		})
+	}
+}
+
+func BenchmarkWrapAll(b *testing.B) {
+	errs := make([]error, 100)
+	for i := range errs {
+		errs[i] = io.EOF
+	}
+	b.Run("Wrap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			wrapped := make([]error, len(errs))
+			for j, err := range errs {
+				wrapped[j] = Wrap(err, "item")
+			}
+			GlobalE = wrapped
+		}
+	})
+	b.Run("WrapAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GlobalE = WrapAll(errs, "item")
+		}
+	})
+}
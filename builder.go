@@ -0,0 +1,84 @@
+package errors
+
+import "fmt"
+
+// A Builder creates an error step by step, for those who find a chain of
+// calls easier to read than nested annotations:
+//
+//	return errors.Build().
+//		Msg("payment failed").
+//		Code("E402").
+//		Field("order", id).
+//		Wrap(err)
+//
+// Builders are values: each method returns a copy, so that a partially
+// built Builder can be shared and completed differently. The zero Builder
+// is ready to use.
+type Builder struct {
+	msg string
+	o   options
+}
+
+// Build returns an empty Builder.
+func Build() Builder {
+	return Builder{}
+}
+
+// Msg sets the message of the error.
+func (b Builder) Msg(message string) Builder {
+	b.msg = message
+	return b
+}
+
+// Msgf sets the message of the error to the format specifier.
+func (b Builder) Msgf(format string, args ...interface{}) Builder {
+	b.msg = fmt.Sprintf(format, args...)
+	return b
+}
+
+// Code sets the code of the error; see WithCode.
+func (b Builder) Code(code string) Builder {
+	b.o.code = code
+	return b
+}
+
+// Kind sets the kind of the error; see WithKind.
+func (b Builder) Kind(kind Kind) Builder {
+	b.o.kind = kind
+	return b
+}
+
+// Field adds the field of key and value to the error; see WithFields.
+func (b Builder) Field(key string, value interface{}) Builder {
+	b.o.fields = append(b.o.fields[:len(b.o.fields):len(b.o.fields)], Field{key, value})
+	return b
+}
+
+// NoStack disables recording a stack trace.
+func (b Builder) NoStack() Builder {
+	b.o.noStack = true
+	return b
+}
+
+// New returns the error built by b, with the stack trace at the point New
+// was called, like New.
+func (b Builder) New() error {
+	if b.o.noStack || !captureEnabled() {
+		return b.o.annotate(formatted{&errorString{b.msg}})
+	}
+	e := &newError{msg: errorString{b.msg}}
+	e.withStack = withStack{&e.msg, callers(0), currentGoroutine()}
+	return b.o.annotate(&e.withStack)
+}
+
+// Wrap returns the error built by b annotating err, with the stack trace
+// at the point Wrap was called, like Wrap. If err is nil, Wrap returns nil.
+func (b Builder) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if b.o.noStack {
+		return b.o.annotate(&withMessage{cause: err, msg: intern(b.msg)})
+	}
+	return b.o.annotate(wrap(err, intern(b.msg), nil))
+}
@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	base := Build().Msg("payment failed").Code("E402")
+	err := base.Field("order", 7).Wrap(io.EOF)
+	if got, want := err.Error(), "payment failed: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if CodeOf(err) != "E402" || !Is(err, io.EOF) {
+		t.Errorf("Wrap: got code %q", CodeOf(err))
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestBuilder" {
+		t.Errorf("Wrap: got stack trace %v", st)
+	}
+
+	a, b := base.Field("a", 1), base.Field("b", 2)
+	if got, want := Fields(a.Field("c", 3).New()), []Field{{"a", 1}, {"c", 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields: got %v, want %v", got, want)
+	}
+	if got, want := Fields(b.New()), []Field{{"b", 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields: got %v, want %v", got, want)
+	}
+
+	err = Build().Msgf("retry %d", 2).Kind("busy").NoStack().New()
+	if err.Error() != "retry 2" || KindOf(err) != "busy" || hasStack(err) {
+		t.Errorf("New: got %+v", err)
+	}
+	if st, ok := stackTraceOf(Build().Msg("new").New()); !ok || st[0].Name() != "github.com/pkg/errors.TestBuilder" {
+		t.Errorf("New: got stack trace %v", st)
+	}
+	if Build().Wrap(nil) != nil {
+		t.Errorf("Wrap(nil): got non-nil error")
+	}
+}
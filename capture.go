@@ -0,0 +1,8 @@
+//go:build !noerrstack
+// +build !noerrstack
+
+package errors
+
+// stackCapture is false when the package is built with the noerrstack build
+// tag, which compiles stack capture out.
+const stackCapture = true
@@ -0,0 +1,17 @@
+//go:build noerrstack
+// +build noerrstack
+
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestNoErrStack(t *testing.T) {
+	for i, err := range []error{New("new"), Errorf("errorf"), WithStack(io.EOF), Wrap(io.EOF, "wrap")} {
+		if HasStack(err) {
+			t.Errorf("test %d: %v has a stack trace with the noerrstack build tag", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSetStackCapture(t *testing.T) {
+	defer SetStackCapture(true)
+
+	SetStackCapture(false)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{New("new"), "new"},
+		{Errorf("errorf %d", 1), "errorf 1"},
+		{WithStack(io.EOF), "EOF"},
+		{EnsureStack(io.EOF), "EOF"},
+		{Wrap(io.EOF, "wrap"), "wrap: EOF"},
+		{Wrapf(io.EOF, "wrapf %d", 1), "wrapf 1: EOF"},
+		{WrapLazy(io.EOF, func() string { return "lazy" }), "lazy: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if hasStack(tt.err) {
+			t.Errorf("test %d: %v has a stack trace while capture is disabled", i+1, tt.err)
+		}
+	}
+
+	SetStackCapture(true)
+	if err := New("new"); !hasStack(err) {
+		t.Errorf("New: no stack trace once capture is enabled again")
+	}
+}
+
+func TestSetSingleFrameCapture(t *testing.T) {
+	defer SetSingleFrameCapture(false)
+
+	SetSingleFrameCapture(true)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{New("new"), "github.com/pkg/errors.TestSetSingleFrameCapture"},
+		{Wrap(io.EOF, "wrap"), "github.com/pkg/errors.TestSetSingleFrameCapture"},
+		{WithStack(io.EOF), "github.com/pkg/errors.TestSetSingleFrameCapture"},
+	}
+	for i, tt := range tests {
+		st, ok := stackTraceOf(tt.err)
+		if !ok || len(st) != 1 {
+			t.Errorf("test %d: got stack trace %v, want a single frame", i+1, st)
+			continue
+		}
+		if got := st[0].Name(); got != tt.want {
+			t.Errorf("test %d: got frame %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	SetSingleFrameCapture(false)
+	if st, _ := stackTraceOf(New("new")); len(st) < 2 {
+		t.Errorf("New: got stack trace %v once single frame capture is disabled", st)
+	}
+}
@@ -0,0 +1,127 @@
+package errors
+
+import "strings"
+
+// isAnnotation reports whether err is one of the wrappers of this package
+// that annotate their cause, with a stack trace for example, without
+// changing its message, or, for Prefix and Suffix, only altering it.
+// Annotations do not count as layers of a chain.
+func isAnnotation(err error) bool {
+	switch err.(type) {
+	case formatted, *withStack, *truncated, *withGoroutineDump, *withCode, *withKind,
+		*withSuppressed, *withStage, *withRetry, *withFields, *withDetached,
+		*withReplacement, *withAffixes, *withHandled, *withStdlibFormat, *withHint,
+		*withExitCode:
+		return true
+	}
+	return false
+}
+
+// layers returns the layers of err's chain, outermost first. A layer is an
+// error together with the annotations wrapping it; it is represented by
+// its outermost annotation so that the stack trace is kept.
+func layers(err error) []error {
+	var ls []error
+	var head error
+	for n := 0; err != nil && n < maxChainDepth; n, err = n+1, Unwrap(err) {
+		if head == nil {
+			head = err
+		}
+		if !isAnnotation(err) {
+			ls = append(ls, head)
+			head = nil
+		}
+	}
+	if head != nil {
+		ls = append(ls, head)
+	}
+	return ls
+}
+
+// Depth returns the number of layers in err's chain: one for every error
+// obtained by repeatedly calling Unwrap, not counting the wrappers this
+// package uses to record stack traces. For example, the depth of New("x")
+// is 1 and the depth of Wrap(io.EOF, "read") is 2.
+func Depth(err error) int {
+	return len(layers(err))
+}
+
+// At returns the layer at index i of err's chain, where index 0 is err
+// itself and index Depth(err)-1 is the innermost layer. At returns nil if
+// i is out of range.
+func At(err error, i int) error {
+	ls := layers(err)
+	if i < 0 || i >= len(ls) {
+		return nil
+	}
+	return ls[i]
+}
+
+// Outermost returns the outermost layer of err's chain, which is err.
+func Outermost(err error) error {
+	return err
+}
+
+// Innermost returns the innermost layer of err's chain, together with the
+// stack trace recorded for it, if any. Innermost returns nil if err is nil.
+func Innermost(err error) error {
+	ls := layers(err)
+	if len(ls) == 0 {
+		return nil
+	}
+	return ls[len(ls)-1]
+}
+
+// Chain returns the layers of err's chain, outermost first, as counted by
+// Depth: Chain(err)[i] is At(err, i). Structured loggers can so emit a
+// chain as an array rather than as its colon-joined message. Chain returns
+// nil if err is nil.
+func Chain(err error) []error {
+	return layers(err)
+}
+
+// Messages returns the message of each layer of err's chain, outermost
+// first, without the messages of the layers it wraps: the messages of
+// Wrap(Wrap(io.EOF, "read"), "load") are "load", "read" and "EOF". The
+// message of an error of another package is its Error() without the
+// message of the layer it wraps if it ends with it, as the wrappers created
+// with fmt.Errorf and %w do, and its whole Error() otherwise.
+func Messages(err error) []string {
+	ls := layers(err)
+	if len(ls) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(ls))
+	for i, l := range ls {
+		var affixes []*withAffixes
+		for isAnnotation(l) {
+			if w, ok := l.(*withAffixes); ok {
+				affixes = append(affixes, w)
+			}
+			l = Unwrap(l)
+		}
+		switch {
+		case i == len(ls)-1:
+			msgs[i] = l.Error()
+		default:
+			msgs[i] = layerMessage(l, ls[i+1].Error())
+		}
+		for j := len(affixes) - 1; j >= 0; j-- {
+			msgs[i] = affixes[j].prefix + msgs[i] + affixes[j].suffix
+		}
+	}
+	return msgs
+}
+
+// layerMessage returns the message of l, a layer wrapping a layer whose
+// message is inner.
+func layerMessage(l error, inner string) string {
+	if w, ok := l.(*withMessage); ok {
+		return w.message()
+	}
+	msg := l.Error()
+	if strings.HasSuffix(msg, ": "+inner) {
+		return msg[:len(msg)-len(inner)-len(": ")]
+	}
+	return msg
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDepthAndAt(t *testing.T) {
+	x := New("x")
+	tests := []struct {
+		err  error
+		want []string
+	}{
+		{nil, nil},
+		{io.EOF, []string{"EOF"}},
+		{x, []string{"x"}},
+		{WithStack(io.EOF), []string{"EOF"}},
+		{Wrap(io.EOF, "read"), []string{"read: EOF", "EOF"}},
+		{Wrap(WithMessage(x, "middle"), "outer"), []string{"outer: middle: x", "middle: x", "x"}},
+		{fmt.Errorf("std: %w", WithStack(x)), []string{"std: x", "x"}},
+		{TruncateStack(Wrap(x, "y"), 1), []string{"y: x", "x"}},
+	}
+
+	for i, tt := range tests {
+		if got := Depth(tt.err); got != len(tt.want) {
+			t.Errorf("test %d: Depth(%v): got %d, want %d", i+1, tt.err, got, len(tt.want))
+		}
+		for j, want := range tt.want {
+			got := At(tt.err, j)
+			if got == nil || got.Error() != want {
+				t.Errorf("test %d: At(%v, %d): got %v, want %q", i+1, tt.err, j, got, want)
+			}
+		}
+		if got := At(tt.err, len(tt.want)); got != nil {
+			t.Errorf("test %d: At(%v, %d): got %v, want nil", i+1, tt.err, len(tt.want), got)
+		}
+		if got := At(tt.err, -1); got != nil {
+			t.Errorf("test %d: At(%v, -1): got %v, want nil", i+1, tt.err, got)
+		}
+	}
+}
+
+func TestOutermostInnermost(t *testing.T) {
+	if Outermost(nil) != nil || Innermost(nil) != nil {
+		t.Errorf("Outermost(nil), Innermost(nil): want nil")
+	}
+
+	err := Wrap(Wrap(io.EOF, "inner"), "outer")
+	if got := Outermost(err); got != err {
+		t.Errorf("Outermost: got %v, want %v", got, err)
+	}
+	in := Innermost(err)
+	if in == nil || in.Error() != "EOF" || !Is(in, io.EOF) {
+		t.Errorf("Innermost: got %v, want EOF", in)
+	}
+	if _, ok := stackTraceOf(in); !ok {
+		t.Errorf("Innermost: got %#v, want the stack trace recorded for io.EOF", in)
+	}
+	if got := Innermost(io.EOF); got != io.EOF {
+		t.Errorf("Innermost(io.EOF): got %v, want io.EOF", got)
+	}
+}
+
+func TestChainAndMessages(t *testing.T) {
+	err := Wrap(fmt.Errorf("open config: %w", WithMessage(WithCode(io.EOF, "E1"), "read")), "load")
+	want := []string{"load", "open config", "read", "EOF"}
+	if got := Messages(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages: got %q, want %q", got, want)
+	}
+	chain := Chain(err)
+	if len(chain) != 4 || chain[0] != err || chain[3].Error() != "EOF" {
+		t.Errorf("Chain: got %v", chain)
+	}
+	for i, l := range chain {
+		if l != At(err, i) {
+			t.Errorf("Chain[%d]: got %v, want %v", i, l, At(err, i))
+		}
+	}
+
+	err = WithMessage(fmt.Errorf("unexpected %s", "input"), "parse")
+	want = []string{"parse", "unexpected input"}
+	if got := Messages(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages: got %q, want %q", got, want)
+	}
+	if Chain(nil) != nil || Messages(nil) != nil {
+		t.Errorf("Chain, Messages: got non-nil for nil")
+	}
+}
@@ -0,0 +1,54 @@
+package errors
+
+// Clone returns a deep copy of err's chain: the wrappers of this package,
+// together with their messages, fields and stack traces, are copied, so
+// that the copy can be annotated or its metadata changed, with Transform
+// for example, without aliasing err, which may be shared. The chain of an
+// error of another package is not copied, nor are the errors of this
+// package that do not wrap another one, which cannot be changed. If err is
+// nil, Clone returns nil. Chains longer than the depth followed by Cause,
+// such as cyclic ones, are only copied up to that depth.
+func Clone(err error) error {
+	return cloneWithin(err, maxChainDepth)
+}
+
+// cloneWithin is like Clone, following at most n errors down each branch
+// of err's chain.
+func cloneWithin(err error, n int) error {
+	if n == 0 {
+		return err
+	}
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *errorString:
+		return &errorString{e.s}
+	case *GroupError:
+		errs := make([]error, len(e.Errors))
+		for i, err := range e.Errors {
+			errs[i] = cloneWithin(err, n-1)
+		}
+		return &GroupError{Errors: errs}
+	}
+	inner := Unwrap(err)
+	if inner == nil {
+		return err
+	}
+	c, ok := rewrap(err, cloneWithin(inner, n-1))
+	if !ok {
+		return err
+	}
+	switch w := c.(type) {
+	case *withStack:
+		w.stack = append(stack(nil), w.stack...)
+	case *withGoroutineDump:
+		w.dump = append([]byte(nil), w.dump...)
+	case *withSuppressed:
+		w.suppressed = append([]error(nil), w.suppressed...)
+	case *withFields:
+		w.fields = append([]Field(nil), w.fields...)
+	case *withDetached:
+		w.fields = append([]Field(nil), w.fields...)
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	err := Wrap(WithFields(WithCode(New("boom"), "E1"), Label("k", 1)), "run")
+	c := Clone(err)
+	if got, want := fmt.Sprintf("%+v", c), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("Clone: got %q, want %q", got, want)
+	}
+	if CodeOf(c) != "E1" || Depth(c) != Depth(err) {
+		t.Errorf("Clone: got code %q and depth %d", CodeOf(c), Depth(c))
+	}
+	Walk(c, func(e error, depth int) bool {
+		Walk(err, func(o error, _ int) bool {
+			if same(e, o) {
+				t.Errorf("Clone: %T at depth %d shared with the original", e, depth)
+			}
+			return true
+		})
+		return true
+	})
+	fc, _ := Find(c, func(err error) bool { _, ok := err.(*withFields); return ok })
+	fc.(*withFields).fields[0].Value = 2
+	if got := Fields(err)[0].Value; got != 1 {
+		t.Errorf("Clone: changing the fields of the copy changed the original to %v", got)
+	}
+
+	foreign := fmt.Errorf("read: %w", io.EOF)
+	if got := Clone(Wrap(foreign, "load")); Unwrap(Unwrap(got)) != foreign {
+		t.Errorf("Clone: copied the error of another package")
+	}
+	if Clone(nil) != nil {
+		t.Errorf("Clone(nil): got non-nil error")
+	}
+}
+
+func TestCloneCyclic(t *testing.T) {
+	c := &cyclic{}
+	c.next = &cyclic{next: c}
+	err := WithMessage(c, "x")
+	got := Clone(err)
+	if got == err {
+		t.Errorf("Clone: got the error itself, want a copy")
+	}
+	if Unwrap(got) != c {
+		t.Errorf("Clone: got cause %v, want the cyclic error as is", Unwrap(got))
+	}
+	if got, want := got.Error(), err.Error(); got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+}
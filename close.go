@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// CloseAndWrap closes c and, if Close fails, wraps its error with the
+// supplied message, as Wrap does. The error is stored in *errp if it is
+// nil, and otherwise attached to it as a suppressed error, retrieved with
+// Suppressed, so that neither error is lost. It is meant to be deferred:
+//
+//	func read(name string) (err error) {
+//		f, err := os.Open(name)
+//		if err != nil {
+//			return err
+//		}
+//		defer errors.CloseAndWrap(f, &err, "closing "+name)
+//		...
+//	}
+func CloseAndWrap(c io.Closer, errp *error, message string) {
+	err := c.Close()
+	if err == nil {
+		return
+	}
+	err = wrap(err, intern(message), nil)
+	if *errp == nil {
+		*errp = err
+		return
+	}
+	*errp = suppress(*errp, err)
+}
+
+// Suppressed returns the errors suppressed by the first error in err's
+// chain that has any, such as the error of a Close call that failed after
+// err occurred; see CloseAndWrap.
+func Suppressed(err error) []error {
+	var errs []error
+	find(err, func(err error) bool {
+		w, ok := err.(*withSuppressed)
+		if ok {
+			errs = append([]error(nil), w.suppressed...)
+		}
+		return ok
+	})
+	return errs
+}
+
+// suppress returns err annotated with the suppressed error s, in addition
+// to those it suppresses already.
+func suppress(err, s error) error {
+	if w, ok := err.(*withSuppressed); ok {
+		return &withSuppressed{w.error, append(w.suppressed[:len(w.suppressed):len(w.suppressed)], s)}
+	}
+	return &withSuppressed{err, []error{s}}
+}
+
+// withSuppressed annotates an error with the errors it suppresses, which
+// are printed by %+v after it.
+type withSuppressed struct {
+	error
+	suppressed []error
+}
+
+func (w *withSuppressed) Cause() error { return w.error }
+
+func (w *withSuppressed) Unwrap() error { return w.error }
+
+func (*withSuppressed) own() {}
+
+func (w *withSuppressed) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", w.error)
+			for _, err := range w.suppressed {
+				fmt.Fprintf(s, "\n\nsuppressed: %+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type closer struct{ err error }
+
+func (c closer) Close() error { return c.err }
+
+func closes(c io.Closer, err error) (rerr error) {
+	defer CloseAndWrap(c, &rerr, "closing")
+	return err
+}
+
+func TestCloseAndWrap(t *testing.T) {
+	if err := closes(closer{}, nil); err != nil {
+		t.Errorf("successful Close: got %v, want nil", err)
+	}
+	primary := New("primary")
+	if err := closes(closer{}, primary); err != primary {
+		t.Errorf("successful Close: got %v, want %v", err, primary)
+	}
+
+	err := closes(closer{io.ErrClosedPipe}, nil)
+	if got, want := err.Error(), "closing: io: read/write on closed pipe"; got != want {
+		t.Errorf("failed Close: got %q, want %q", got, want)
+	}
+	if !Is(err, io.ErrClosedPipe) {
+		t.Errorf("Is(%v, io.ErrClosedPipe): got false, want true", err)
+	}
+
+	err = closes(closer{io.ErrClosedPipe}, primary)
+	err = closes(closer{io.EOF}, err)
+	if err.Error() != "primary" || !Is(err, primary) || Is(err, io.EOF) {
+		t.Errorf("failed Close after an error: got %v, want %v", err, primary)
+	}
+	suppressed := Suppressed(Wrap(err, "wrapped"))
+	if len(suppressed) != 2 || !Is(suppressed[0], io.ErrClosedPipe) || !Is(suppressed[1], io.EOF) {
+		t.Errorf("Suppressed(%v): got %v", err, suppressed)
+	}
+	out := fmt.Sprintf("%+v", err)
+	for _, want := range []string{
+		"primary\ngithub.com/pkg/errors.TestCloseAndWrap\n",
+		"\n\nsuppressed: closing: io: read/write on closed pipe\ngithub.com/pkg/errors.closes\n",
+		"\n\nsuppressed: closing: EOF\ngithub.com/pkg/errors.closes\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%%+v: got %q, want it to contain %q", out, want)
+		}
+	}
+	if got := fmt.Sprint(err); got != "primary" {
+		t.Errorf("%%v: got %q, want %q", got, "primary")
+	}
+	if Suppressed(primary) != nil {
+		t.Errorf("Suppressed(%v): got %v, want nil", primary, Suppressed(primary))
+	}
+}
@@ -0,0 +1,129 @@
+package errors
+
+import "fmt"
+
+// Kind is a class of errors, such as "not found" or "timeout", that callers
+// can act on without inspecting the errors themselves. Kinds are declared
+// as constants by the packages that use them:
+//
+//	const NotFound errors.Kind = "not found"
+type Kind string
+
+// WithCode annotates err with code, a machine-readable identifier of the
+// error such as "E1234", without changing its message.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	return &withCode{err, intern(code)}
+}
+
+// WithKind annotates err with kind without changing its message.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+	return &withKind{err, kind}
+}
+
+// CodeOf returns the code of the first error in err's chain that has one,
+// or the empty string. An error has a code if it was annotated with
+// WithCode or if it has an ErrorCode() string method.
+func CodeOf(err error) string {
+	var code string
+	find(err, func(err error) bool {
+		e, ok := err.(interface{ ErrorCode() string })
+		if ok {
+			code = e.ErrorCode()
+		}
+		return ok
+	})
+	return code
+}
+
+// IsCode reports whether the code of err is code; see CodeOf.
+func IsCode(err error, code string) bool {
+	return CodeOf(err) == code && code != ""
+}
+
+// KindOf returns the kind of the first error in err's chain that has one,
+// or the empty Kind. An error has a kind if it was annotated with WithKind
+// or if it has an ErrorKind() Kind method.
+func KindOf(err error) Kind {
+	var kind Kind
+	find(err, func(err error) bool {
+		e, ok := err.(interface{ ErrorKind() Kind })
+		if ok {
+			kind = e.ErrorKind()
+		}
+		return ok
+	})
+	return kind
+}
+
+// IsKind reports whether the kind of err is kind; see KindOf.
+func IsKind(err error, kind Kind) bool {
+	return KindOf(err) == kind && kind != ""
+}
+
+// find calls match with each error in err's chain, depth first, until it
+// returns true, and reports whether it did. Unlike As, find does not
+// allocate, which matters for classifications made on every request.
+func find(err error, match func(error) bool) bool {
+	_, found := findWithin(err, match, maxChainDepth)
+	return found
+}
+
+// findWithin is like find, following at most n errors. It returns the
+// number of errors left to follow.
+func findWithin(err error, match func(error) bool, n int) (int, bool) {
+	for ; err != nil && n > 0; n-- {
+		if match(err) {
+			return n, true
+		}
+		if u, ok := err.(interface{ Unwrap() []error }); ok {
+			n--
+			for _, err := range u.Unwrap() {
+				var found bool
+				if n, found = findWithin(err, match, n); found {
+					return n, true
+				}
+			}
+			return n, false
+		}
+		err = Unwrap(err)
+	}
+	return n, false
+}
+
+type withCode struct {
+	error
+	code string
+}
+
+func (w *withCode) ErrorCode() string { return w.code }
+
+func (w *withCode) Cause() error { return w.error }
+
+func (w *withCode) Unwrap() error { return w.error }
+
+func (w *withCode) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withCode) own() {}
+
+type withKind struct {
+	error
+	kind Kind
+}
+
+func (w *withKind) ErrorKind() Kind { return w.kind }
+
+func (w *withKind) Cause() error { return w.error }
+
+func (w *withKind) Unwrap() error { return w.error }
+
+func (w *withKind) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withKind) own() {}
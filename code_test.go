@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+const notFound Kind = "not found"
+
+type codedError struct{}
+
+func (codedError) Error() string     { return "coded" }
+func (codedError) ErrorCode() string { return "E42" }
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithCode(io.EOF, "E1"), "E1"},
+		{Wrap(WithCode(io.EOF, "E1"), "wrapped"), "E1"},
+		{WithCode(WithCode(io.EOF, "E1"), "E2"), "E2"},
+		{fmt.Errorf("wrapped: %w", WithCode(io.EOF, "E1")), "E1"},
+		{codedError{}, "E42"},
+		{Wrap(codedError{}, "wrapped"), "E42"},
+	}
+	for i, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("test %d: CodeOf(%v): got %q, want %q", i+1, tt.err, got, tt.want)
+		}
+		if tt.want != "" && !IsCode(tt.err, tt.want) {
+			t.Errorf("test %d: IsCode(%v, %q): got false, want true", i+1, tt.err, tt.want)
+		}
+	}
+	if IsCode(io.EOF, "") {
+		t.Errorf("IsCode(io.EOF, \"\"): got true, want false")
+	}
+	if WithCode(nil, "E1") != nil {
+		t.Errorf("WithCode(nil): got non-nil error")
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	err := Wrap(WithKind(New("no such user"), notFound), "lookup")
+	if got := KindOf(err); got != notFound {
+		t.Errorf("KindOf(%v): got %q, want %q", err, got, notFound)
+	}
+	if !IsKind(err, notFound) {
+		t.Errorf("IsKind(%v, %q): got false, want true", err, notFound)
+	}
+	if got, want := err.Error(), "lookup: no such user"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if KindOf(io.EOF) != "" || IsKind(io.EOF, notFound) {
+		t.Errorf("KindOf(io.EOF): got %q, want no kind", KindOf(io.EOF))
+	}
+	if got := Depth(err); got != 2 {
+		t.Errorf("Depth(%v): got %d, want 2", err, got)
+	}
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompactStack returns the stack trace of the first error in err's chain
+// that has one on a single line, innermost frame first, each frame printed
+// as its function, qualified by the last element of its package path, and
+// its file and line, such as
+//
+//	main.run@cmd/app/main.go:42 < svc.Do@svc.go:17 < ...
+//
+// so that it fits in a field of a structured log entry. Files are rendered
+// according to the options set with SetPathOptions. At most n frames are
+// printed, followed by "< ..." if the stack trace has more; n <= 0 prints
+// every frame. CompactStack returns the empty string if no error of err's
+// chain has a stack trace.
+func CompactStack(err error, n int) string {
+	st, ok := stackTraceOf(err)
+	if !ok {
+		return ""
+	}
+	st = st.visible()
+	more := n > 0 && len(st) > n
+	if more {
+		st = st[:n]
+	}
+	var b strings.Builder
+	for i, f := range st {
+		if i > 0 {
+			b.WriteString(" < ")
+		}
+		name := f.Name()
+		b.WriteString(name[strings.LastIndex(name, "/")+1:])
+		b.WriteString("@")
+		b.WriteString(f.path())
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(f.Line()))
+	}
+	if more {
+		b.WriteString(" < ...")
+	}
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCompactStack(t *testing.T) {
+	if got := CompactStack(io.EOF, 3); got != "" {
+		t.Errorf("CompactStack(io.EOF): got %q, want empty", got)
+	}
+
+	err := Wrap(io.EOF, "read")
+	st, _ := stackTraceOf(err)
+	if len(st) < 2 {
+		t.Fatalf("got stack trace %v, want at least two frames", st)
+	}
+	first := fmt.Sprintf("errors.TestCompactStack@%s:%d", st[0].path(), st[0].Line())
+	if got, want := CompactStack(err, 1), first+" < ..."; got != want {
+		t.Errorf("CompactStack(err, 1): got %q, want %q", got, want)
+	}
+	got := CompactStack(err, 0)
+	if !strings.HasPrefix(got, first+" < testing.tRunner@") {
+		t.Errorf("CompactStack(err, 0): got %q, want it to start with %q", got, first)
+	}
+	if strings.Count(got, " < ") != len(st)-1 || strings.ContainsAny(got, "\n\t") || strings.HasSuffix(got, "...") {
+		t.Errorf("CompactStack(err, 0): got %q, want the %d frames on one line", got, len(st))
+	}
+	if got := CompactStack(err, len(st)); strings.HasSuffix(got, "...") {
+		t.Errorf("CompactStack(err, %d): got %q, want no ellipsis", len(st), got)
+	}
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SetStdlibFormatting makes the errors of this package print with the
+// verbs %s, %v, %q, %x and %X, and with %+v, exactly as the errors of the
+// standard library wrapping the same messages do: fmt then applies the
+// flags, width and precision of the verb to the message, and %+v prints
+// the message alone, without stack trace. It is meant for programs whose
+// log format is checked against golden output produced with fmt.Errorf;
+// StdlibFormat enables it for a single error. The other verbs, %#v among
+// them, are not affected. Stdlib formatting is disabled by default.
+func SetStdlibFormatting(enabled bool) {
+	updateConfig(func(c *config) { c.stdlibFormat = enabled })
+}
+
+// StdlibFormat annotates err so that it prints as described by
+// SetStdlibFormatting, whatever the process-wide setting, as do the errors
+// wrapping it:
+//
+//	return errors.StdlibFormat(errors.Wrap(err, "read config"))
+//
+// If err is nil, StdlibFormat returns nil.
+func StdlibFormat(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withStdlibFormat{err}
+}
+
+type withStdlibFormat struct {
+	error
+}
+
+func (w *withStdlibFormat) Cause() error { return w.error }
+
+func (w *withStdlibFormat) Unwrap() error { return w.error }
+
+func (w *withStdlibFormat) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withStdlibFormat) own() {}
+
+// formatStdlib formats err as fmt formats the errors of the standard
+// library, if stdlib formatting is enabled for err and verb is one fmt
+// prints the message of errors with, and reports whether it did.
+func formatStdlib(s fmt.State, verb rune, err error) bool {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			return false
+		}
+	case 's', 'q', 'x', 'X':
+	default:
+		return false
+	}
+	if !loadConfig().stdlibFormat && !find(err, func(err error) bool {
+		_, ok := err.(*withStdlibFormat)
+		return ok
+	}) {
+		return false
+	}
+	fmt.Fprintf(s, directive(s, verb), err.Error())
+	return true
+}
+
+// directive returns the formatting directive s was created for, such as
+// "%-10.3s".
+func directive(s fmt.State, verb rune) string {
+	b := []byte{'%'}
+	for _, f := range "+-# 0" {
+		if s.Flag(int(f)) {
+			b = append(b, byte(f))
+		}
+	}
+	if w, ok := s.Width(); ok {
+		b = strconv.AppendInt(b, int64(w), 10)
+	}
+	if p, ok := s.Precision(); ok {
+		b = append(b, '.')
+		b = strconv.AppendInt(b, int64(p), 10)
+	}
+	return string(append(b, string(verb)...))
+}
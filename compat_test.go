@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSetStdlibFormatting(t *testing.T) {
+	defer SetStdlibFormatting(false)
+
+	SetStdlibFormatting(true)
+	err := Wrap(New("inner"), "outer")
+	std := fmt.Errorf("outer: %w", fmt.Errorf("inner"))
+	for _, format := range []string{"%s", "%v", "%+v", "%q", "%+q", "%x", "% X", "%-14v|", "%14s|", "%.5v"} {
+		if got, want := fmt.Sprintf(format, err), fmt.Sprintf(format, std); got != want {
+			t.Errorf("Sprintf(%q): got %q, want %q", format, got, want)
+		}
+	}
+
+	SetStdlibFormatting(false)
+	if got := fmt.Sprintf("%-14v|", err); got != "outer: inner|" {
+		t.Errorf("disabled: got %q, want %q", got, "outer: inner|")
+	}
+}
+
+func TestStdlibFormat(t *testing.T) {
+	err := Wrap(StdlibFormat(Wrap(io.EOF, "read")), "load")
+	if got, want := fmt.Sprintf("%+v", err), "load: read: EOF"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%8.4s|", err), "    load|"; got != want {
+		t.Errorf("%%8.4s: got %q, want %q", got, want)
+	}
+	if fmt.Sprintf("%+v", Wrap(io.EOF, "read")) == "read: EOF" {
+		t.Errorf("%%+v printed no stack trace for an error not annotated by StdlibFormat")
+	}
+	if StdlibFormat(nil) != nil {
+		t.Errorf("StdlibFormat(nil): got non-nil error")
+	}
+}
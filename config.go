@@ -0,0 +1,296 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// config holds the process-wide settings of the package. A config value is
+// never modified once published; setters copy the current value, change the
+// copy and publish it, so readers always observe a consistent snapshot.
+type config struct {
+	// stackFilter reports whether a frame should be kept when a stack
+	// trace is formatted. A nil stackFilter keeps every frame.
+	stackFilter func(Frame) bool
+
+	// mainModule overrides the module path read from the build
+	// information; see SetMainModule.
+	mainModule string
+
+	// appMarker is printed in front of application frames.
+	appMarker string
+
+	// paths holds the rules set with SetPathOptions.
+	paths *pathRules
+
+	// sourceContext is the number of source lines printed around
+	// application frames; see SetSourceContext.
+	sourceContext int
+
+	// stackLimit is the number of frames kept at each end of long stack
+	// traces; see SetStackLimit.
+	stackLimit int
+
+	// goroutineCapture enables recording goroutine IDs with stack traces.
+	goroutineCapture bool
+
+	// frameFormatter replaces the default rendering of frames in stack
+	// traces; see SetFrameFormatter.
+	frameFormatter func(w io.Writer, f Frame, verbose bool)
+
+	// deferFormatting defers the formatting of the messages of Errorf
+	// and Wrapf; see SetDeferredFormatting.
+	deferFormatting bool
+
+	// noStackCapture disables capturing stack traces; see
+	// SetStackCapture.
+	noStackCapture bool
+
+	// sampling is the fraction of stack traces captured, if sampled is
+	// set; see SetStackSampling.
+	sampled  bool
+	sampling float64
+
+	// singleFrame restricts captured stack traces to their innermost
+	// frame; see SetSingleFrameCapture.
+	singleFrame bool
+
+	// rawFrames makes MarshalText encode program counters; see
+	// SetRawFrames.
+	rawFrames bool
+
+	// reporter reports the errors nobody waits for; see SetReporter.
+	reporter func(err error)
+
+	// duplicateWraps enables detecting duplicate wraps; see
+	// SetDuplicateWrapDetection.
+	duplicateWraps bool
+
+	// stdlibFormat formats errors as the standard library does; see
+	// SetStdlibFormatting.
+	stdlibFormat bool
+
+	// pkgErrorsFormat formats errors as github.com/pkg/errors does; see
+	// SetPkgErrorsFormatting.
+	pkgErrorsFormat bool
+
+	// format is the layout of %+v; see SetFormat.
+	format FormatConfig
+
+	// catalog translates messages; see SetCatalog.
+	catalog Catalog
+
+	// symbolic selects the frames printed symbolically; see
+	// SetSymbolicFrames.
+	symbolic SymbolicFrames
+}
+
+var (
+	configMu sync.Mutex   // serialises writers
+	configV  atomic.Value // *config
+)
+
+func init() {
+	configV.Store(&config{duplicateWraps: debugMode})
+}
+
+// loadConfig returns the current configuration snapshot.
+func loadConfig() *config {
+	return configV.Load().(*config)
+}
+
+// updateConfig applies fn to a copy of the current configuration and
+// publishes the result.
+func updateConfig(fn func(c *config)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	c := *loadConfig()
+	fn(&c)
+	configV.Store(&c)
+}
+
+// Config holds the process-wide settings of the package, each of which can
+// also be changed on its own with the corresponding setter, such as
+// SetStackLimit for StackLimit. The zero Config is not the default
+// configuration: start from CurrentConfig to change some settings only.
+type Config struct {
+	// StackCapture enables capturing stack traces; see SetStackCapture.
+	StackCapture bool
+
+	// StackSampling is the fraction of stack traces captured; see
+	// SetStackSampling.
+	StackSampling float64
+
+	// SingleFrameCapture restricts stack traces to the frame of their
+	// caller; see SetSingleFrameCapture.
+	SingleFrameCapture bool
+
+	// GoroutineCapture enables recording goroutine IDs with stack traces;
+	// see SetGoroutineCapture.
+	GoroutineCapture bool
+
+	// DeferredFormatting defers the formatting of messages; see
+	// SetDeferredFormatting.
+	DeferredFormatting bool
+
+	// StackFilter selects the frames printed; see SetStackFilter.
+	StackFilter func(Frame) bool
+
+	// StackLimit is the number of frames kept at each end of long stack
+	// traces; see SetStackLimit.
+	StackLimit int
+
+	// SourceContext is the number of source lines printed around
+	// application frames; see SetSourceContext.
+	SourceContext int
+
+	// MainModule overrides the path of the main module; see
+	// SetMainModule.
+	MainModule string
+
+	// ApplicationFrameMarker is printed in front of application frames;
+	// see SetApplicationFrameMarker.
+	ApplicationFrameMarker string
+
+	// Paths sets how source file paths are rendered; see SetPathOptions.
+	Paths PathOptions
+
+	// FrameFormatter replaces the rendering of frames; see
+	// SetFrameFormatter.
+	FrameFormatter func(w io.Writer, f Frame, verbose bool)
+
+	// RawFrames makes MarshalText encode program counters; see
+	// SetRawFrames.
+	RawFrames bool
+
+	// Reporter reports the errors nobody waits for; see SetReporter.
+	Reporter func(err error)
+
+	// DuplicateWrapDetection enables detecting duplicate wraps; see
+	// SetDuplicateWrapDetection.
+	DuplicateWrapDetection bool
+
+	// StdlibFormatting formats errors as the standard library does; see
+	// SetStdlibFormatting.
+	StdlibFormatting bool
+
+	// PkgErrorsFormatting formats errors as github.com/pkg/errors does;
+	// see SetPkgErrorsFormatting.
+	PkgErrorsFormatting bool
+
+	// Format is the layout of %+v; see SetFormat.
+	Format FormatConfig
+
+	// Catalog translates messages; see SetCatalog.
+	Catalog Catalog
+
+	// SymbolicFrames selects the frames printed symbolically; see
+	// SetSymbolicFrames.
+	SymbolicFrames SymbolicFrames
+
+	// Interning is the number of distinct messages and codes interned;
+	// see SetInterning. The interned strings are not part of the settings
+	// readers observe at once: Configure sets it after the others.
+	Interning int
+
+	// SymbolCacheSize is the number of program counters whose symbols are
+	// cached; see SetSymbolCacheSize. Like Interning, Configure sets it
+	// after the others.
+	SymbolCacheSize int
+}
+
+// CurrentConfig returns the current settings of the package.
+func CurrentConfig() Config {
+	c := loadConfig()
+	cfg := Config{
+		StackCapture:           !c.noStackCapture,
+		StackSampling:          1,
+		SingleFrameCapture:     c.singleFrame,
+		GoroutineCapture:       c.goroutineCapture,
+		DeferredFormatting:     c.deferFormatting,
+		StackFilter:            c.stackFilter,
+		StackLimit:             c.stackLimit,
+		SourceContext:          c.sourceContext,
+		MainModule:             c.mainModule,
+		ApplicationFrameMarker: c.appMarker,
+		FrameFormatter:         c.frameFormatter,
+		RawFrames:              c.rawFrames,
+		Reporter:               c.reporter,
+		DuplicateWrapDetection: c.duplicateWraps,
+		StdlibFormatting:       c.stdlibFormat,
+		PkgErrorsFormatting:    c.pkgErrorsFormat,
+		Format:                 c.format,
+		Catalog:                c.catalog,
+		SymbolicFrames:         c.symbolic,
+	}
+	if c.sampled {
+		cfg.StackSampling = c.sampling
+	}
+	interned.mu.RLock()
+	cfg.Interning = interned.size
+	interned.mu.RUnlock()
+	symbols.mu.Lock()
+	cfg.SymbolCacheSize = symbols.size
+	symbols.mu.Unlock()
+	if c.paths != nil {
+		cfg.Paths = c.paths.opts
+		cfg.Paths.Rewrites = append([]PathRewrite(nil), cfg.Paths.Rewrites...)
+		if urls := cfg.Paths.ModuleURLs; urls != nil {
+			cfg.Paths.ModuleURLs = make(map[string]string, len(urls))
+			for m, url := range urls {
+				cfg.Paths.ModuleURLs[m] = url
+			}
+		}
+	}
+	return cfg
+}
+
+// Configure replaces all the settings of the package with cfg at once:
+// concurrent readers observe either the previous settings or cfg, never a
+// mix of both. It is safe to call at any time, for example to adjust the
+// settings of a service under load:
+//
+//	cfg := errors.CurrentConfig()
+//	cfg.StackSampling = 0.01
+//	cfg.StackLimit = 10
+//	errors.Configure(cfg)
+func Configure(cfg Config) {
+	paths := compilePaths(cfg.Paths)
+	updateConfig(func(c *config) {
+		*c = config{
+			stackFilter:      cfg.StackFilter,
+			mainModule:       strings.TrimSuffix(cfg.MainModule, "/"),
+			appMarker:        cfg.ApplicationFrameMarker,
+			paths:            paths,
+			sourceContext:    nonNegative(cfg.SourceContext),
+			stackLimit:       nonNegative(cfg.StackLimit),
+			goroutineCapture: cfg.GoroutineCapture,
+			frameFormatter:   cfg.FrameFormatter,
+			deferFormatting:  cfg.DeferredFormatting,
+			noStackCapture:   !cfg.StackCapture,
+			sampled:          cfg.StackSampling < 1,
+			sampling:         cfg.StackSampling,
+			singleFrame:      cfg.SingleFrameCapture,
+			rawFrames:        cfg.RawFrames,
+			reporter:         cfg.Reporter,
+			duplicateWraps:   cfg.DuplicateWrapDetection,
+			stdlibFormat:     cfg.StdlibFormatting,
+			pkgErrorsFormat:  cfg.PkgErrorsFormatting,
+			format:           cfg.Format,
+			catalog:          cfg.Catalog,
+			symbolic:         cfg.SymbolicFrames,
+		}
+	})
+	SetInterning(cfg.Interning)
+	SetSymbolCacheSize(cfg.SymbolCacheSize)
+}
+
+// nonNegative returns n, or 0 if n is negative.
+func nonNegative(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
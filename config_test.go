@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	defer Configure(CurrentConfig())
+
+	err := Wrap(io.EOF, "configured")
+	before := fmt.Sprintf("%+v", err)
+	Configure(CurrentConfig())
+	if after := fmt.Sprintf("%+v", err); after != before {
+		t.Errorf("Configure(CurrentConfig()): output changed from %q to %q", before, after)
+	}
+
+	cfg := CurrentConfig()
+	cfg.StackLimit = 3
+	cfg.SourceContext = -1
+	cfg.StackSampling = 0.5
+	cfg.MainModule = "example.com/app/"
+	cfg.Interning = 4
+	cfg.SymbolCacheSize = 100
+	cfg.Paths.Rewrites = []PathRewrite{{Prefix: "/src/", Replace: ""}}
+	Configure(cfg)
+	cfg.Paths.Rewrites[0].Prefix = "/changed/"
+
+	got := CurrentConfig()
+	want := cfg
+	want.SourceContext = 0
+	want.MainModule = "example.com/app"
+	want.Paths.Rewrites = []PathRewrite{{Prefix: "/src/", Replace: ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CurrentConfig():\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestConfigureConcurrently(t *testing.T) {
+	defer Configure(CurrentConfig())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cfg := CurrentConfig()
+				cfg.StackLimit = i
+				Configure(cfg)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fmt.Fprintf(ioutil.Discard, "%+v", Wrap(io.EOF, "concurrent"))
+			}
+		}()
+	}
+	wg.Wait()
+}
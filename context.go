@@ -0,0 +1,312 @@
+//go:build go1.20
+// +build go1.20
+
+package errors
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The keys of the fields WrapContext records.
+const (
+	contextDone     = "context.done"
+	contextCause    = "context.cause"
+	contextDeadline = "context.deadline_remaining"
+)
+
+// WrapContext returns an error annotating err with a stack trace at the
+// point WrapContext is called, and the supplied message, like Wrap. It also
+// records the state of ctx at that point as fields: "context.done", whether
+// it was done, "context.cause", its cause as returned by context.Cause if
+// it was done, and "context.deadline_remaining", the time.Duration left
+// until its deadline, negative once passed, if it has one, followed by the
+// fields of ctx returned by FieldsFromContext. The stack trace is captured
+// as configured by the options of ctx; see WithOptions. These tell apart
+// an operation that failed on its own from one that failed because its
+// context was cancelled or timed out:
+//
+//	if err := db.QueryContext(ctx, q); err != nil {
+//		return errors.WrapContext(ctx, err, "query")
+//	}
+//
+// If err is nil, WrapContext returns nil.
+func WrapContext(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	c := contextConfig(ctx)
+	return &withFields{wrapWith(c, 0, err, intern(message), nil), contextFields(ctx)}
+}
+
+// extractors holds the functions registered with RegisterContextExtractor,
+// as a []func(context.Context) []Field never modified once stored.
+var extractors struct {
+	mu sync.Mutex // serialises writers
+	v  atomic.Value
+}
+
+// RegisterContextExtractor registers fn to extract fields from contexts,
+// typically the request-scoped values middleware stores in them, such as
+// the ID of the request or of the user, so that they are attached to every
+// error wrapped with the context by WrapContext. fn must be safe to call
+// concurrently, and return nil for contexts with none of its values:
+//
+//	errors.RegisterContextExtractor(func(ctx context.Context) []errors.Field {
+//		if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+//			return []errors.Field{errors.Label("request", id)}
+//		}
+//		return nil
+//	})
+//
+// Extractors are usually registered during initialisation; they apply in
+// the order they were registered and cannot be unregistered.
+func RegisterContextExtractor(fn func(ctx context.Context) []Field) {
+	extractors.mu.Lock()
+	defer extractors.mu.Unlock()
+	fns, _ := extractors.v.Load().([]func(context.Context) []Field)
+	extractors.v.Store(append(fns[:len(fns):len(fns)], fn))
+}
+
+// FieldsFromContext returns the fields the extractors registered with
+// RegisterContextExtractor extract from ctx, in the order they were
+// registered, followed by the default fields of ctx; see DefaultFields.
+func FieldsFromContext(ctx context.Context) []Field {
+	fns, _ := extractors.v.Load().([]func(context.Context) []Field)
+	var fields []Field
+	for _, fn := range fns {
+		fields = append(fields, fn(ctx)...)
+	}
+	if o := optionsOf(ctx); o != nil {
+		fields = append(fields, o.fields...)
+	}
+	return fields
+}
+
+// A ContextOption changes how the errors created with a context are
+// created; see WithOptions.
+type ContextOption func(o *contextOptions)
+
+// contextOptions holds the options of a context.
+type contextOptions struct {
+	// config holds the changes to the configuration of the package the
+	// options make, in the order they apply.
+	config []func(c *config)
+
+	// fields holds the fields set with DefaultFields.
+	fields []Field
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a copy of ctx carrying opts, in addition to the
+// options ctx already carries, which override the process-wide settings of
+// the package for the errors created with the context, by WrapContext for
+// example. Batch jobs and request paths running in the same process can so
+// follow different policies:
+//
+//	ctx = errors.WithOptions(ctx,
+//		errors.SampleStacks(0.01),
+//		errors.DefaultFields(errors.Label("job", name)),
+//	)
+func WithOptions(ctx context.Context, opts ...ContextOption) context.Context {
+	o := &contextOptions{}
+	if parent := optionsOf(ctx); parent != nil {
+		o.config = append(o.config, parent.config...)
+		o.fields = append(o.fields, parent.fields...)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return context.WithValue(ctx, optionsKey{}, o)
+}
+
+// CaptureStacks enables or disables capturing stack traces; see
+// SetStackCapture.
+func CaptureStacks(enabled bool) ContextOption {
+	return func(o *contextOptions) {
+		o.config = append(o.config, func(c *config) { c.noStackCapture = !enabled })
+	}
+}
+
+// SampleStacks sets the fraction of stack traces captured; see
+// SetStackSampling.
+func SampleStacks(rate float64) ContextOption {
+	return func(o *contextOptions) {
+		o.config = append(o.config, func(c *config) {
+			c.sampled = rate < 1
+			c.sampling = rate
+		})
+	}
+}
+
+// DefaultFields adds fields to the fields attached to the errors created
+// with the context; see FieldsFromContext.
+func DefaultFields(fields ...Field) ContextOption {
+	return func(o *contextOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// optionsOf returns the options ctx carries, or nil if it has none.
+func optionsOf(ctx context.Context) *contextOptions {
+	o, _ := ctx.Value(optionsKey{}).(*contextOptions)
+	return o
+}
+
+// contextConfig returns the configuration of the package changed by the
+// options of ctx.
+func contextConfig(ctx context.Context) *config {
+	o := optionsOf(ctx)
+	if o == nil || len(o.config) == 0 {
+		return loadConfig()
+	}
+	c := *loadConfig()
+	for _, fn := range o.config {
+		fn(&c)
+	}
+	return &c
+}
+
+// NewContext returns an error with the supplied message, recording the
+// stack trace at the point it was called, like New, and the fields of ctx,
+// like WrapContext: its state, and the fields returned by
+// FieldsFromContext. If ctx is done, the cause of its cancellation is so
+// attached to the error, reported by CancelCause.
+func NewContext(ctx context.Context, message string) error {
+	c := contextConfig(ctx)
+	fields := contextFields(ctx)
+	if !capturing(c) {
+		return &withFields{&errorString{message}, fields}
+	}
+	e := &newError{msg: errorString{message}}
+	e.withStack = withStack{&e.msg, callersWith(c, 0), currentGoroutine()}
+	return &withFields{&e.withStack, fields}
+}
+
+// ErrorfContext formats according to a format specifier and returns the
+// error recording the stack trace at the point ErrorfContext was called,
+// like Errorf, and the fields of ctx, like NewContext.
+func ErrorfContext(ctx context.Context, format string, args ...interface{}) error {
+	c := contextConfig(ctx)
+	err := errorf(format, args)
+	if capturing(c) && !hasStack(err) {
+		err = &withStack{err, callersWith(c, 0), currentGoroutine()}
+	}
+	return &withFields{err, contextFields(ctx)}
+}
+
+// contextFields returns the fields recording the state of ctx.
+func contextFields(ctx context.Context) []Field {
+	done := ctx.Err() != nil
+	fields := make([]Field, 1, 3)
+	fields[0] = Field{contextDone, done}
+	if done {
+		fields = append(fields, Field{contextCause, context.Cause(ctx)})
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, Field{contextDeadline, time.Until(deadline)})
+	}
+	return append(fields, FieldsFromContext(ctx)...)
+}
+
+// CancelCause returns the cause of the cancellation of the context an error
+// of err's chain was wrapped with by WrapContext, as returned by
+// context.Cause when it was wrapped, or nil if no such context was done.
+func CancelCause(err error) error {
+	var cause error
+	find(err, func(err error) bool {
+		if w, ok := err.(*withFields); ok {
+			for _, f := range w.fields {
+				if f.Key == contextCause {
+					cause, _ = f.Value.(error)
+					return true
+				}
+			}
+		}
+		return false
+	})
+	return cause
+}
+
+// IsCanceled reports whether err is, or was caused by, the cancellation of
+// a context: whether err's chain, or the chain of its CancelCause, contains
+// context.Canceled.
+func IsCanceled(err error) bool {
+	return Is(err, context.Canceled) || Is(CancelCause(err), context.Canceled)
+}
+
+// IsTimeout reports whether err is, or was caused by, a timeout: whether
+// err's chain, or the chain of its CancelCause, contains
+// context.DeadlineExceeded or an error with a Timeout method reporting
+// true, such as the errors of package net.
+func IsTimeout(err error) bool {
+	return isTimeout(err) || isTimeout(CancelCause(err))
+}
+
+func isTimeout(err error) bool {
+	return find(err, func(err error) bool {
+		if err == context.DeadlineExceeded {
+			return true
+		}
+		t, ok := err.(interface{ Timeout() bool })
+		return ok && t.Timeout()
+	})
+}
+
+// WithCancelCause is like context.WithCancelCause, except that the cause
+// cancel is called with is annotated with a stack trace at the point cancel
+// is called, unless it already has one, so that context.Cause and
+// CancelCause locate where the context was cancelled. Calling cancel with
+// nil sets the cause to context.Canceled, annotated likewise.
+func WithCancelCause(parent context.Context) (ctx context.Context, cancel context.CancelCauseFunc) {
+	ctx, cancelCause := context.WithCancelCause(parent)
+	return ctx, func(cause error) {
+		if cause == nil {
+			cause = context.Canceled
+		}
+		cancelCause(ensureStack(cause))
+	}
+}
+
+// Detach annotates err with a snapshot of the fields of ctx, as returned by
+// FieldsFromContext, so that err can be parked, in a queue for example, and
+// outlive ctx without retaining it. The fields are reported by Fields, and
+// restored by Reattach when the work err belongs to is picked up later:
+//
+//	queue.Push(job{err: errors.Detach(ctx, err)})
+//
+// If err is nil, Detach returns nil.
+func Detach(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withDetached{err, FieldsFromContext(ctx)}
+}
+
+// Reattach returns a copy of ctx carrying, as default fields, the snapshot
+// of fields Detach annotated err with, so that the errors created with the
+// returned context carry the request-scoped values of the context err was
+// detached from, such as the ID of the request, in addition to those of
+// ctx:
+//
+//	j := queue.Pop()
+//	ctx := errors.Reattach(ctx, j.err)
+//
+// If no error of err's chain was annotated by Detach, Reattach returns ctx.
+func Reattach(ctx context.Context, err error) context.Context {
+	var fields []Field
+	ok := find(err, func(err error) bool {
+		w, ok := err.(*withDetached)
+		if ok {
+			fields = w.fields
+		}
+		return ok
+	})
+	if !ok {
+		return ctx
+	}
+	return WithOptions(ctx, DefaultFields(fields...))
+}
@@ -0,0 +1,235 @@
+//go:build go1.20
+// +build go1.20
+
+package errors
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWrapContext(t *testing.T) {
+	err := WrapContext(context.Background(), io.EOF, "read")
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if fields := Fields(err); len(fields) != 1 || fields[0] != Label("context.done", false) {
+		t.Errorf("Fields: got %v for a live context", fields)
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestWrapContext" {
+		t.Errorf("WrapContext: got stack trace %v", st)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	cancel()
+	fields := Fields(WrapContext(ctx, io.EOF, "read"))
+	if len(fields) != 3 || fields[0] != Label("context.done", true) || fields[1] != Label("context.cause", context.Canceled) {
+		t.Fatalf("Fields: got %v for a cancelled context", fields)
+	}
+	if d, ok := fields[2].Value.(time.Duration); fields[2].Key != "context.deadline_remaining" || !ok || d <= 0 || d > time.Hour {
+		t.Errorf("Fields: got %v for the deadline", fields[2])
+	}
+
+	if WrapContext(ctx, nil, "read") != nil {
+		t.Errorf("WrapContext(nil): got non-nil error")
+	}
+}
+
+type requestKey struct{}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	fns, _ := extractors.v.Load().([]func(context.Context) []Field)
+	defer extractors.v.Store(fns)
+
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		if id, ok := ctx.Value(requestKey{}).(string); ok {
+			return []Field{Label("request", id)}
+		}
+		return nil
+	})
+	RegisterContextExtractor(func(context.Context) []Field {
+		return []Field{Label("service", "api")}
+	})
+
+	ctx := context.WithValue(context.Background(), requestKey{}, "r1")
+	want := []Field{Label("request", "r1"), Label("service", "api")}
+	if got := FieldsFromContext(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldsFromContext: got %v, want %v", got, want)
+	}
+	want = append([]Field{Label("context.done", false)}, want...)
+	if got := Fields(WrapContext(ctx, io.EOF, "read")); !reflect.DeepEqual(got, want) {
+		t.Errorf("WrapContext: got fields %v, want %v", got, want)
+	}
+	want = []Field{Label("context.done", false), Label("service", "api")}
+	if got := Fields(WrapContext(context.Background(), io.EOF, "read")); !reflect.DeepEqual(got, want) {
+		t.Errorf("WrapContext: got fields %v, want %v", got, want)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o timeout" }
+
+func (timeoutError) Timeout() bool { return true }
+
+func TestCancelCause(t *testing.T) {
+	if CancelCause(WrapContext(context.Background(), io.EOF, "read")) != nil {
+		t.Errorf("CancelCause: got a cause for a live context")
+	}
+
+	ctx, cancel := WithCancelCause(context.Background())
+	cancel(io.ErrUnexpectedEOF)
+	cause := context.Cause(ctx)
+	if cause == nil || cause.Error() != "unexpected EOF" || !Is(cause, io.ErrUnexpectedEOF) {
+		t.Fatalf("context.Cause: got %v", cause)
+	}
+	if st, ok := stackTraceOf(cause); !ok || st[0].Name() != "github.com/pkg/errors.TestCancelCause" {
+		t.Errorf("WithCancelCause: got stack trace %v", st)
+	}
+	err := WrapContext(ctx, io.EOF, "read")
+	if got := CancelCause(Wrap(err, "load")); got != cause {
+		t.Errorf("CancelCause: got %v, want %v", got, cause)
+	}
+	if IsCanceled(err) || IsTimeout(err) {
+		t.Errorf("IsCanceled, IsTimeout: true for a cancellation caused by %v", cause)
+	}
+
+	ctx, cancel = WithCancelCause(context.Background())
+	cancel(nil)
+	if cause := context.Cause(ctx); !Is(cause, context.Canceled) || !hasStack(cause) {
+		t.Errorf("context.Cause: got %v after cancel(nil)", cause)
+	}
+	if err := WrapContext(ctx, io.EOF, "read"); !IsCanceled(err) || IsTimeout(err) {
+		t.Errorf("IsCanceled, IsTimeout: got %t, %t for %v", IsCanceled(err), IsTimeout(err), err)
+	}
+
+	ctx, cancel2 := context.WithTimeout(context.Background(), 0)
+	defer cancel2()
+	<-ctx.Done()
+	tests := []struct {
+		err      error
+		canceled bool
+		timeout  bool
+	}{
+		{io.EOF, false, false},
+		{Wrap(context.Canceled, "read"), true, false},
+		{Wrap(context.DeadlineExceeded, "read"), false, true},
+		{Wrap(timeoutError{}, "read"), false, true},
+		{WrapContext(ctx, io.EOF, "read"), false, true},
+	}
+	for i, tt := range tests {
+		if got := IsCanceled(tt.err); got != tt.canceled {
+			t.Errorf("test %d: IsCanceled(%v): got %t, want %t", i+1, tt.err, got, tt.canceled)
+		}
+		if got := IsTimeout(tt.err); got != tt.timeout {
+			t.Errorf("test %d: IsTimeout(%v): got %t, want %t", i+1, tt.err, got, tt.timeout)
+		}
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	ctx := WithOptions(context.Background(), CaptureStacks(false), DefaultFields(Label("job", "backup")))
+	err := WrapContext(ctx, io.EOF, "read")
+	if hasStack(err) {
+		t.Errorf("WrapContext: got a stack trace with capture disabled")
+	}
+	want := []Field{Label("context.done", false), Label("job", "backup")}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("WrapContext: got fields %v, want %v", got, want)
+	}
+	if !hasStack(Wrap(io.EOF, "read")) {
+		t.Errorf("Wrap: no stack trace, options of a context applied globally")
+	}
+
+	ctx = WithOptions(ctx, CaptureStacks(true), DefaultFields(Label("shard", 2)))
+	err = WrapContext(ctx, io.EOF, "read")
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestWithOptions" {
+		t.Errorf("WrapContext: got stack trace %v", st)
+	}
+	want = []Field{Label("job", "backup"), Label("shard", 2)}
+	if got := FieldsFromContext(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldsFromContext: got %v, want %v", got, want)
+	}
+
+	ctx = WithOptions(context.Background(), SampleStacks(0))
+	err = WrapContext(ctx, io.EOF, "read")
+	if st, ok := stackTraceOf(err); !ok || len(st) != 0 {
+		t.Errorf("WrapContext: got stack trace %v, want an unsampled one", st)
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	ctx, cancel := WithCancelCause(WithOptions(context.Background(), DefaultFields(Label("job", "backup"))))
+	cancel(io.ErrClosedPipe)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewContext(ctx, "interrupted"), "interrupted"},
+		{ErrorfContext(ctx, "interrupted at %d", 3), "interrupted at 3"},
+		{ErrorfContext(ctx, "interrupted: %w", io.EOF), "interrupted: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if st, ok := stackTraceOf(tt.err); !ok || st[0].Name() != "github.com/pkg/errors.TestNewContext" {
+			t.Errorf("test %d: got stack trace %v", i+1, st)
+		}
+		if cause := CancelCause(tt.err); !Is(cause, io.ErrClosedPipe) {
+			t.Errorf("test %d: CancelCause: got %v", i+1, cause)
+		}
+		if fields := Fields(tt.err); len(fields) != 3 || fields[2] != Label("job", "backup") {
+			t.Errorf("test %d: got fields %v", i+1, fields)
+		}
+	}
+	if !Is(tests[2].err, io.EOF) {
+		t.Errorf("ErrorfContext: %v does not wrap io.EOF", tests[2].err)
+	}
+
+	ctx = WithOptions(context.Background(), CaptureStacks(false))
+	if err := NewContext(ctx, "interrupted"); hasStack(err) || err.Error() != "interrupted" {
+		t.Errorf("NewContext: got %+v with capture disabled", err)
+	}
+	if err := ErrorfContext(ctx, "interrupted"); hasStack(err) {
+		t.Errorf("ErrorfContext: got %+v with capture disabled", err)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	fns, _ := extractors.v.Load().([]func(context.Context) []Field)
+	defer extractors.v.Store(fns)
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		if id, ok := ctx.Value(requestKey{}).(string); ok {
+			return []Field{Label("request", id)}
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), requestKey{}, "r1"))
+	err := Detach(ctx, Wrap(io.EOF, "read"))
+	cancel()
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	want := []Field{Label("request", "r1")}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields: got %v, want %v", got, want)
+	}
+
+	worker := WithOptions(context.Background(), DefaultFields(Label("worker", 1)))
+	ctx = Reattach(worker, Wrap(err, "process"))
+	want = []Field{Label("context.done", false), Label("worker", 1), Label("request", "r1")}
+	if got := Fields(NewContext(ctx, "retry")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewContext: got fields %v, want %v", got, want)
+	}
+	if got := Reattach(worker, io.EOF); got != worker {
+		t.Errorf("Reattach: got a new context for an error without snapshot")
+	}
+	if Detach(ctx, nil) != nil {
+		t.Errorf("Detach(nil): got non-nil error")
+	}
+}
@@ -0,0 +1,73 @@
+//go:build go1.21
+// +build go1.21
+
+package errors
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// DeadlineError is the cause of the cancellation of a context whose named
+// deadline, set with NamedDeadline, was exceeded. It matches
+// context.DeadlineExceeded, so that Is and IsTimeout report it as such.
+type DeadlineError struct {
+	// Name is the name of the deadline.
+	Name string
+
+	// Duration is the timeout the deadline was set with.
+	Duration time.Duration
+}
+
+func (e *DeadlineError) Error() string {
+	return "deadline " + strconv.Quote(e.Name) + " exceeded after " + e.Duration.String()
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *DeadlineError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// Timeout reports true, like the errors of package net that are timeouts.
+func (e *DeadlineError) Timeout() bool { return true }
+
+func (*DeadlineError) own() {}
+
+// NamedDeadline is like context.WithTimeout, except that when the deadline
+// is exceeded the cause of the cancellation of the returned context, as
+// returned by context.Cause, is a *DeadlineError with the given name. When
+// an operation fails with context.DeadlineExceeded under several nested
+// timeouts, wrapping its error with WrapContext records which of them
+// fired, reported by DeadlineName:
+//
+//	ctx, cancel := errors.NamedDeadline(ctx, "db-query", 2*time.Second)
+//	defer cancel()
+//	if err := db.QueryContext(ctx, q); err != nil {
+//		return errors.WrapContext(ctx, err, "query")
+//	}
+func NamedDeadline(parent context.Context, name string, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(parent, d, &DeadlineError{Name: name, Duration: d})
+}
+
+// DeadlineName returns the name of the deadline, set with NamedDeadline,
+// that caused err: the name of the first *DeadlineError in err's chain or,
+// if there is none, in the chain of its CancelCause. It returns the empty
+// string if err was not caused by a named deadline.
+func DeadlineName(err error) string {
+	if name, ok := deadlineName(err); ok {
+		return name
+	}
+	name, _ := deadlineName(CancelCause(err))
+	return name
+}
+
+func deadlineName(err error) (string, bool) {
+	var name string
+	ok := find(err, func(err error) bool {
+		e, ok := err.(*DeadlineError)
+		if ok {
+			name = e.Name
+		}
+		return ok
+	})
+	return name, ok
+}
@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNamedDeadline(t *testing.T) {
+	ctx, cancel := NamedDeadline(context.Background(), "request", time.Hour)
+	defer cancel()
+	inner, cancelInner := NamedDeadline(ctx, "db-query", time.Millisecond)
+	defer cancelInner()
+	<-inner.Done()
+
+	cause := context.Cause(inner)
+	if got, want := cause.Error(), `deadline "db-query" exceeded after 1ms`; got != want {
+		t.Errorf("context.Cause: got %q, want %q", got, want)
+	}
+	if !Is(cause, context.DeadlineExceeded) {
+		t.Errorf("Is(%v, context.DeadlineExceeded): got false", cause)
+	}
+
+	err := Wrap(WrapContext(inner, inner.Err(), "query"), "load")
+	if got := DeadlineName(err); got != "db-query" {
+		t.Errorf("DeadlineName: got %q, want %q", got, "db-query")
+	}
+	if !IsTimeout(err) {
+		t.Errorf("IsTimeout(%v): got false", err)
+	}
+	if got := DeadlineName(Wrap(cause, "query")); got != "db-query" {
+		t.Errorf("DeadlineName: got %q for the cause itself", got)
+	}
+	if got := DeadlineName(WrapContext(ctx, context.Canceled, "query")); got != "" {
+		t.Errorf("DeadlineName: got %q for a live context", got)
+	}
+}
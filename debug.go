@@ -0,0 +1,8 @@
+//go:build !errdebug
+// +build !errdebug
+
+package errors
+
+// debugMode is true when the package is built with the errdebug build tag,
+// which enables the checks meant for development by default.
+const debugMode = false
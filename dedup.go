@@ -0,0 +1,37 @@
+package errors
+
+// SetDuplicateWrapDetection enables or disables detecting duplicate wraps:
+// while enabled, Wrap and the other functions annotating an error with a
+// message and the stack trace of their caller return the error unchanged
+// if its outermost layer was created by the same call site with the same
+// message, as happens when a retry loop wraps the same error on every
+// attempt:
+//
+//	err := read(buf)
+//	for attempt := 1; err != nil && attempt < 3; attempt++ {
+//		err = errors.Wrap(err, "read failed") // not "read failed: read failed: …"
+//		if read(buf) == nil {
+//			err = nil
+//		}
+//	}
+//
+// Detection makes wrapping errors slightly more expensive. It is disabled
+// by default, unless the package is built with the errdebug build tag.
+func SetDuplicateWrapDetection(enabled bool) {
+	updateConfig(func(c *config) { c.duplicateWraps = enabled })
+}
+
+// isDuplicateWrap reports whether the outermost layer of err was created
+// with the message msg by the call site of the program counter pc.
+func isDuplicateWrap(err error, msg string, pc uintptr) bool {
+	for isAnnotation(err) {
+		err = Unwrap(err)
+	}
+	w, ok := err.(*withMessage)
+	if !ok || w.pc == 0 || w.lazy != nil || w.msg != msg {
+		return false
+	}
+	// The same call site has different program counters where the
+	// function making it is inlined.
+	return w.pc == pc || FrameFromPC(w.pc).Key() == FrameFromPC(pc).Key()
+}
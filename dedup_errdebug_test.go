@@ -0,0 +1,19 @@
+//go:build errdebug
+// +build errdebug
+
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestErrDebugDuplicateWraps(t *testing.T) {
+	err := error(io.EOF)
+	for i := 0; i < 3; i++ {
+		err = Wrap(err, "read")
+	}
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Wrap: got %q, want %q with the errdebug build tag", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSetDuplicateWrapDetection(t *testing.T) {
+	defer SetDuplicateWrapDetection(debugMode)
+
+	retry := func(enabled bool) error {
+		SetDuplicateWrapDetection(enabled)
+		err := error(io.EOF)
+		for i := 0; i < 3; i++ {
+			err = Wrap(err, "read")
+		}
+		return err
+	}
+	if got, want := retry(false).Error(), "read: read: read: EOF"; got != want {
+		t.Errorf("Wrap: got %q, want %q without detection", got, want)
+	}
+	err := retry(true)
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Wrap: got %q, want %q with detection", got, want)
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestSetDuplicateWrapDetection.func1" {
+		t.Errorf("Wrap: got stack trace %v", st)
+	}
+
+	read := func(err error) error { return Wrap(err, "read") }
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{read(read(io.EOF)), "read: EOF"},
+		{read(WithFields(read(io.EOF), Label("attempt", 2))), "read: EOF"},
+		{read(WithMessage(read(io.EOF), "read")), "read: read: read: EOF"},
+		{Wrap(read(io.EOF), "read"), "read: read: EOF"},
+		{read(Clone(read(io.EOF))), "read: EOF"},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Equal reports whether the chains of a and b are made of the same layers:
+// layers with the same messages, as returned by Messages, and the same
+// codes and kinds, whatever their stack traces and types. Errors created by
+// different call sites, or in different processes, can so be compared, in
+// tests or to deduplicate the errors reported by the replicas of a service.
+func Equal(a, b error) bool {
+	la, lb := describeLayers(a), describeLayers(b)
+	if len(la) != len(lb) {
+		return false
+	}
+	for i := range la {
+		if la[i] != lb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns a comparison of the chains of a and b in the style of a
+// unified diff, one line per layer describing its message, escaped as in a
+// Go string literal, code and kind, outermost first, or the empty string if
+// they are Equal:
+//
+//	--- a
+//	+++ b
+//	  load
+//	- read [code=E1]
+//	+ write [code=E2]
+//	  EOF
+func Diff(a, b error) string {
+	la, lb := describeLayers(a), describeLayers(b)
+	// lcs[i][j] is the length of the longest common subsequence of la[i:]
+	// and lb[j:].
+	lcs := make([][]int, len(la)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(lb)+1)
+	}
+	for i := len(la) - 1; i >= 0; i-- {
+		for j := len(lb) - 1; j >= 0; j-- {
+			switch {
+			case la[i] == lb[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	if lcs[0][0] == len(la) && len(la) == len(lb) {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("--- a\n+++ b\n")
+	i, j := 0, 0
+	for i < len(la) || j < len(lb) {
+		switch {
+		case i < len(la) && j < len(lb) && la[i] == lb[j]:
+			out.WriteString("  " + la[i] + "\n")
+			i++
+			j++
+		case j == len(lb) || i < len(la) && lcs[i+1][j] >= lcs[i][j+1]:
+			out.WriteString("- " + la[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + lb[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
+
+// describeLayers returns a line describing each layer of err's chain, made
+// of its message followed by its code and kind, if any.
+func describeLayers(err error) []string {
+	ls := layers(err)
+	msgs := Messages(err)
+	lines := make([]string, len(ls))
+	for i, l := range ls {
+		var code string
+		var kind Kind
+		for e := l; e != nil; e = Unwrap(e) {
+			if c, ok := e.(interface{ ErrorCode() string }); ok && code == "" {
+				code = c.ErrorCode()
+			}
+			if k, ok := e.(interface{ ErrorKind() Kind }); ok && kind == "" {
+				kind = k.ErrorKind()
+			}
+			if !isAnnotation(e) {
+				break
+			}
+		}
+		line := strconv.Quote(msgs[i])
+		line = line[1 : len(line)-1]
+		var attrs []string
+		if code != "" {
+			attrs = append(attrs, "code="+code)
+		}
+		if kind != "" {
+			attrs = append(attrs, "kind="+string(kind))
+		}
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, " ") + "]"
+		}
+		lines[i] = line
+	}
+	return lines
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	newErr := func(code string) error {
+		return Wrap(WithCode(Wrap(io.EOF, "read"), code), "load")
+	}
+	tests := []struct {
+		a, b  error
+		equal bool
+	}{
+		{newErr("E1"), newErr("E1"), true},
+		{newErr("E1"), fmt.Errorf("load: %w", WithCode(fmt.Errorf("read: %w", io.EOF), "E1")), true},
+		{newErr("E1"), newErr("E2"), false},
+		{newErr("E1"), Wrap(io.EOF, "load"), false},
+		{WithKind(io.EOF, "eof"), io.EOF, false},
+		{nil, nil, true},
+		{nil, io.EOF, false},
+	}
+	for i, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.equal {
+			t.Errorf("test %d: Equal(%v, %v): got %t, want %t", i+1, tt.a, tt.b, got, tt.equal)
+		}
+		if got := Diff(tt.a, tt.b); (got == "") != tt.equal {
+			t.Errorf("test %d: Diff(%v, %v): got %q", i+1, tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := Wrap(WithCode(Wrap(io.EOF, "read"), "E1"), "load")
+	b := Wrap(WithCode(Wrap(io.EOF, "write\n"), "E2"), "load")
+	want := "--- a\n+++ b\n  load\n- read [code=E1]\n+ write\\n [code=E2]\n  EOF\n"
+	if got := Diff(a, b); got != want {
+		t.Errorf("Diff:\n got %q\nwant %q", got, want)
+	}
+	want = "--- a\n+++ b\n+ load\n  EOF\n"
+	if got := Diff(io.EOF, WithMessage(io.EOF, "load")); got != want {
+		t.Errorf("Diff:\n got %q\nwant %q", got, want)
+	}
+}
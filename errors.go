@@ -62,6 +62,9 @@
 //     %v    see %s
 //     %+v   extended format. Each Frame of the error's StackTrace will
 //           be printed in detail.
+//     %#v   Go-like syntax, printing the type of the error, its message,
+//           the type of its cause and the number of frames of its
+//           StackTrace.
 //
 // Retrieving the stack trace of an error or wrapper
 //
@@ -96,27 +99,67 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"strings"
+	"sync"
 )
 
 // New returns an error with the supplied message.
-// New also records the stack trace at the point it was called.
-func New(message string) error {
-	return formatted{withStack{
-		error: errors.New(message),
-		stack: callers(0),
-	}}
+// New also records the stack trace at the point it was called, unless
+// opts include NoStack, and annotates the error as set by opts:
+//
+//	err := errors.New("no such user", errors.Code("E404"), errors.Fieldsf("user", id))
+//
+// Without options, New makes at most two allocations.
+func New(message string, opts ...Option) error {
+	o := newOptions(opts)
+	if o.stackless() || !captureEnabled() {
+		return o.annotate(formatted{errors.New(message)})
+	}
+	e := &newError{msg: errorString{message}}
+	e.withStack = withStack{&e.msg, callers(0), currentGoroutine()}
+	return o.annotate(&e.withStack)
+}
+
+// newError is the error New returns, a withStack annotating an errorString,
+// both allocated at once.
+type newError struct {
+	withStack
+	msg errorString
 }
 
+// errorString is a trivial implementation of error, as returned by the
+// standard library's errors.New.
+type errorString struct {
+	s string
+}
+
+func (e *errorString) Error() string { return e.s }
+
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
-// Errorf also records the stack trace at the point it was called.
+// Errorf also records the stack trace at the point it was called, unless
+// an error wrapped with the %w verb already has one: the stack trace of
+// the wrapped error is then the one printed by %+v.
 func Errorf(format string, args ...interface{}) error {
-	return formatted{withStack{
-		error: fmt.Errorf(format, args...),
+	err := errorf(format, args)
+	if !captureEnabled() || hasStack(err) {
+		return formatted{err}
+	}
+	return formatted{&withStack{
+		error: err,
 		stack: callers(0),
+		goid:  currentGoroutine(),
 	}}
 }
 
+// NewNoStack returns an error with the supplied message, without recording
+// a stack trace, for hot paths where the cost of capturing one is not
+// acceptable. The error otherwise behaves like the errors returned by New.
+func NewNoStack(message string) error {
+	return formatted{errors.New(message)}
+}
+
 // WithStack is an alias for EnsureStack. Deprecated.
 func WithStack(err error) error {
 	return ensureStack(err)
@@ -134,37 +177,165 @@ func ensureStack(err error) error {
 	if err == nil {
 		return nil
 	}
-	var st interface {
-		error
-		StackTrace() StackTrace
-	}
-	if As(err, &st) {
+	if !captureEnabled() || hasStack(err) {
 		return formatted{err}
 	}
-	return formatted{withStack{
+	return formatted{&withStack{
 		err,
 		callers(1),
+		currentGoroutine(),
 	}}
 }
 
+// wrap returns err annotated with the message msg, or the message computed
+// by lazy if it is not nil, and, unless err has one, the stack trace of the
+// caller of the function calling wrap. Both annotations are allocated at
+// once.
+func wrap(err error, msg string, lazy *lazyMessage) error {
+	return wrapWith(loadConfig(), 1, err, msg, lazy)
+}
+
+// wrapWith is like wrap, capturing the stack trace as configured by c and
+// skipping skip more frames.
+func wrapWith(c *config, skip int, err error, msg string, lazy *lazyMessage) error {
+	var pc uintptr
+	if c.duplicateWraps {
+		var pcs [1]uintptr
+		runtime.Callers(3+skip, pcs[:])
+		pc = pcs[0]
+		if lazy == nil && isDuplicateWrap(err, msg, pc) {
+			return err
+		}
+	}
+	if !capturing(c) || hasStack(err) {
+		return &withMessage{cause: err, msg: msg, lazy: lazy, pc: pc}
+	}
+	w := &wrapped{}
+	w.withStack = withStack{err, callersWith(c, 1+skip), currentGoroutine()}
+	w.withMessage.cause = &w.withStack
+	w.withMessage.msg = msg
+	w.withMessage.lazy = lazy
+	w.withMessage.pc = pc
+	return &w.withMessage
+}
+
+// wrapped is the error wrap returns when it records a stack trace.
+type wrapped struct {
+	withMessage
+	withStack withStack
+}
+
+// wrapStack returns the stack trace recorded along with the message of w,
+// by Wrap, if any: the withStack w wraps, unless it is the one New records
+// with an errorString.
+func (w *withMessage) wrapStack() (*withStack, bool) {
+	ws, ok := w.cause.(*withStack)
+	if !ok {
+		return nil, false
+	}
+	_, isNew := ws.error.(*errorString)
+	return ws, !isNew
+}
+
+// hasStack reports whether any error in err's chain has a stack trace.
+func hasStack(err error) bool {
+	_, ok := firstStackTracer(err)
+	return ok
+}
+
+// HasStack reports whether any error in err's chain has a stack trace.
+// It is much cheaper than the equivalent call to As for chains made of the
+// errors of this package.
+func HasStack(err error) bool {
+	return hasStack(err)
+}
+
+// stackTracer is the interface, documented in the package comment, of the
+// errors that have a stack trace.
+type stackTracer interface {
+	error
+	StackTrace() StackTrace
+}
+
+// own is implemented by the error types of this package. None of them has
+// an As method, so their chains can be searched without the reflection As
+// relies on. The chains of other errors are searched the same way up to
+// the first error with an As method or several wrapped errors.
+type own interface {
+	own()
+}
+
+// maxChainDepth is the maximum number of errors followed along a chain by
+// the functions of this package. Chains are expected to be much shorter: a
+// longer one is assumed to be made cyclic by an error wrapping itself,
+// directly or not, so that following it would never end.
+const maxChainDepth = 1000
+
+// ErrCyclicChain is returned by Cause for the errors whose chain is longer
+// than the errors of this package follow, typically because an error of
+// another package wraps itself, directly or not.
+var ErrCyclicChain = errors.New("errors: chain too long, possibly cyclic")
+
+// firstStackTracer returns the first error in err's chain that has a stack
+// trace.
+func firstStackTracer(err error) (stackTracer, bool) {
+	for n := 0; err != nil && n < maxChainDepth; n++ {
+		if st, ok := err.(stackTracer); ok {
+			return st, true
+		}
+		if _, ok := err.(own); !ok {
+			switch err.(type) {
+			case interface{ As(interface{}) bool }, interface{ Unwrap() []error }:
+				var st stackTracer
+				ok := As(err, &st)
+				return st, ok
+			}
+		}
+		err = Unwrap(err)
+	}
+	return nil, false
+}
+
 type withStack struct {
 	error
-	*stack
+	stack
+	goid int64 // ID of the capturing goroutine, 0 if not recorded
 }
 
-func (w withStack) Cause() error { return w.error }
+func (w *withStack) Cause() error { return w.error }
 
 // Unwrap provides compatibility for Go 1.13 error chains.
-func (w withStack) Unwrap() error { return w.error }
+func (w *withStack) Unwrap() error { return w.error }
+
+func (w *withStack) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withStack) own() {}
+
+func (w *withStack) writeStack(s io.Writer, o frameOptions) {
+	if w.goid != 0 {
+		writeGoroutine(s, w.goid)
+	}
+	if w.stack == nil {
+		io.WriteString(s, "\n(stack trace not sampled)")
+		return
+	}
+	w.StackTrace().visible().writeFrames(s, o)
+}
 
 // Wrap returns an error annotating err with a stack trace
-// at the point Wrap is called, and the supplied message.
+// at the point Wrap is called, unless opts include NoStack, and the
+// supplied message, and annotates it as set by opts; see New.
 // If err is nil, Wrap returns nil.
-func Wrap(err error, message string) error {
+// Without options, Wrap makes at most two allocations.
+func Wrap(err error, message string, opts ...Option) error {
 	if err == nil {
 		return nil
 	}
-	return formatted{fmt.Errorf("%s: %w", message, ensureStack(err))}
+	o := newOptions(opts)
+	if o.stackless() {
+		return o.annotate(&withMessage{cause: err, msg: intern(message)})
+	}
+	return o.annotate(wrap(err, intern(message), nil))
 }
 
 // Wrapf returns an error annotating err with a stack trace
@@ -174,9 +345,120 @@ func Wrapf(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	return formatted{fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), ensureStack(err))}
+	if loadConfig().deferFormatting {
+		return wrap(err, "", &lazyMessage{format: format, args: args})
+	}
+	return wrap(err, intern(fmt.Sprintf(format, args...)), nil)
 }
 
+// WrapIf returns an error annotating err with a stack trace at the point
+// WrapIf is called, and the supplied message, like Wrap, if cond is true,
+// and err otherwise.
+func WrapIf(cond bool, err error, message string) error {
+	if err == nil || !cond {
+		return err
+	}
+	return wrap(err, intern(message), nil)
+}
+
+// WrapUnless returns an error annotating err with a stack trace at the
+// point WrapUnless is called, and the supplied message, like Wrap, unless
+// err matches target, as defined by Is, in which case it returns err
+// unchanged. It leaves the errors callers expect, such as io.EOF, as they
+// are, so that they can still compare them with ==:
+//
+//	n, err := r.Read(buf)
+//	return n, errors.WrapUnless(err, io.EOF, "read header")
+func WrapUnless(err, target error, message string) error {
+	if err == nil || Is(err, target) {
+		return err
+	}
+	return wrap(err, intern(message), nil)
+}
+
+// WrapNoStack returns an error annotating err with the supplied message,
+// like Wrap, but without recording a stack trace if err has none.
+// If err is nil, WrapNoStack returns nil.
+func WrapNoStack(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &withMessage{cause: err, msg: intern(message)}
+}
+
+// withMessage prefixes the message of its cause with msg. The message is
+// only concatenated when Error is first called and is then remembered:
+// like every error of this package, withMessage assumes that the message of
+// its cause does not change once created. Annotating an error always
+// creates a new wrapper rather than modifying an existing one.
+type withMessage struct {
+	cause error
+	msg   string
+	lazy  *lazyMessage // if not nil, computes msg when first needed
+	pc    uintptr      // call site, only recorded to detect duplicate wraps
+
+	once sync.Once
+	err  string // the result of Error
+}
+
+func (w *withMessage) message() string {
+	if w.lazy != nil {
+		return w.lazy.String()
+	}
+	return w.msg
+}
+
+// Error returns the message of w followed by that of its cause. Only the
+// first call allocates.
+func (w *withMessage) Error() string {
+	w.once.Do(func() { w.err = w.join() })
+	return w.err
+}
+
+// join returns the message of w followed by that of its cause. The
+// messages of consecutive withMessage errors in the chain are joined at
+// once, so that the message of a long chain is built in a single
+// allocation rather than one per layer.
+func (w *withMessage) join() string {
+	n := 0
+	tail := w.segments(func(m string) { n += len(m) + len(": ") })
+	msg := tail.Error()
+	var b strings.Builder
+	b.Grow(n + len(msg))
+	w.segments(func(m string) {
+		b.WriteString(m)
+		b.WriteString(": ")
+	})
+	b.WriteString(msg)
+	return b.String()
+}
+
+// segments calls fn with the message of w and of each withMessage error
+// following it in the chain, skipping the annotations that do not change
+// the message, and returns the first error that is neither.
+func (w *withMessage) segments(fn func(m string)) error {
+	var err error = w
+	for {
+		switch e := err.(type) {
+		case *withMessage:
+			fn(e.message())
+			err = e.cause
+		case formatted, *withStack:
+			err = Unwrap(e)
+		default:
+			return err
+		}
+	}
+}
+
+func (w *withMessage) Cause() error { return w.cause }
+
+func (w *withMessage) Unwrap() error { return w.cause }
+
+func (w *withMessage) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withMessage) own() {}
+
 type formatted struct {
 	error
 }
@@ -185,35 +467,77 @@ func (f formatted) Cause() error { return f.error }
 
 func (f formatted) Unwrap() error { return f.error }
 
-func (f formatted) Format(s fmt.State, verb rune) {
+func (f formatted) Format(s fmt.State, verb rune) { formatError(s, verb, f) }
+
+func (formatted) own() {}
+
+// formatError formats err, an error of this package, according to the
+// fmt.Formatter interface. %+v prints the message of err followed by the
+// stack trace of the first error in its chain that has one, and %#v prints
+// a summary of err in a Go-like syntax.
+func formatError(s fmt.State, verb rune, err error) {
+	if formatStdlib(s, verb, err) {
+		return
+	}
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			writeGoSyntax(s, err)
+			return
+		}
 		if s.Flag('+') {
-			io.WriteString(s, f.error.Error())
-			var st interface {
-				Format(fmt.State, rune)
-				StackTrace() StackTrace
+			c := loadConfig()
+			if c.pkgErrorsFormat {
+				writePkgErrors(s, err)
+				return
+			}
+			io.WriteString(s, c.format.message(err))
+			if !c.format.HideFields {
+				writeFields(s, err)
 			}
-			if As(f.error, &st) {
-				st.Format(s, verb)
+			if c.format.FrameStyle != NoFrames {
+				writeStackOf(s, err, frameOptions{})
 			}
 			return
 		}
 		fallthrough
 	case 's':
-		io.WriteString(s, f.error.Error())
+		io.WriteString(s, err.Error())
 	case 'q':
-		fmt.Fprintf(s, "%q", f.error.Error())
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
+// stackWriter is implemented by the errors of this package that record a
+// stack trace, or alter how it is printed, to write it in the %+v layout.
+type stackWriter interface {
+	writeStack(w io.Writer, o frameOptions)
+}
+
+// writeStackOf writes the stack trace of the first error in err's chain
+// that has one in the %+v layout.
+func writeStackOf(w io.Writer, err error, o frameOptions) {
+	for n := 0; err != nil && n < maxChainDepth; n, err = n+1, Unwrap(err) {
+		switch e := err.(type) {
+		case stackWriter:
+			e.writeStack(w, o)
+			return
+		case interface{ StackTrace() StackTrace }:
+			e.StackTrace().visible().writeFrames(w, o)
+			return
+		}
 	}
 }
 
 // WithMessage annotates err with a new message.
 // If err is nil, WithMessage returns nil.
+// The message of err is not copied: the messages of a chain of annotations
+// are only joined when Error is first called on it.
 func WithMessage(err error, message string) error {
 	if err == nil {
 		return nil
 	}
-	return fmt.Errorf("%s: %w", message, err)
+	return &withMessage{cause: err, msg: intern(message)}
 }
 
 // WithMessagef annotates err with the format specifier.
@@ -222,24 +546,45 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 	if err == nil {
 		return nil
 	}
-	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+	return &withMessage{cause: err, msg: intern(fmt.Sprintf(format, args...))}
 }
 
 // Cause calls Unwrap on err repeatedly, until the error has a StackTrace()
-// or does not implement Unwrap.
+// or does not implement Unwrap. Unlike the Cause of github.com/pkg/errors,
+// it so returns the outermost error with a stack trace of err's chain,
+// rather than its innermost error, which RootCause returns.
 func Cause(err error) error {
-	for {
-		var st interface {
-			error
-			StackTrace() StackTrace
+	if st, ok := firstStackTracer(err); ok {
+		return st
+	}
+	for n := 0; ; n++ {
+		e := Unwrap(err)
+		if e == nil {
+			return err
 		}
-		if As(err, &st) {
-			return st
+		if n == maxChainDepth {
+			return ErrCyclicChain
 		}
+		err = e
+	}
+}
+
+// RootCause returns the innermost error of err's chain, obtained by
+// calling Unwrap repeatedly until it returns nil, whether the errors of the
+// chain have stack traces or not: the root cause of Wrap(io.EOF, "read") is
+// io.EOF, where its Cause is the error recording its stack trace. An error
+// joining several errors, with an Unwrap() []error method, is the root
+// cause of its chain. RootCause returns ErrCyclicChain for chains too long
+// to be followed, like Cause.
+func RootCause(err error) error {
+	for n := 0; ; n++ {
 		e := Unwrap(err)
 		if e == nil {
 			return err
 		}
+		if n == maxChainDepth {
+			return ErrCyclicChain
+		}
 		err = e
 	}
 }
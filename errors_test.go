@@ -249,3 +249,178 @@ func TestErrorEquality(t *testing.T) {
 		}
 	}
 }
+
+func TestNoStack(t *testing.T) {
+	if got := WrapNoStack(nil, "no error"); got != nil {
+		t.Errorf("WrapNoStack(nil, \"no error\"): got %#v, expected nil", got)
+	}
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NewNoStack("no stack"), "no stack"},
+		{WrapNoStack(io.EOF, "no stack"), "no stack: EOF"},
+		{WrapNoStack(NewNoStack("inner"), "outer"), "outer: inner"},
+	}
+	for i, tt := range tests {
+		for _, format := range []string{"%s", "%v", "%+v"} {
+			if got := fmt.Sprintf(format, tt.err); got != tt.want {
+				t.Errorf("test %d: Sprintf(%q): got %q, want %q", i+1, format, got, tt.want)
+			}
+		}
+		if got := fmt.Sprintf("%q", tt.err); got != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("test %d: Sprintf(%%q): got %s, want %q", i+1, got, tt.want)
+		}
+		var st interface{ StackTrace() StackTrace }
+		if As(tt.err, &st) {
+			t.Errorf("test %d: %v has a stack trace", i+1, tt.err)
+		}
+	}
+
+	if err := WrapNoStack(io.EOF, "x"); !Is(err, io.EOF) {
+		t.Errorf("Is(WrapNoStack(io.EOF, \"x\"), io.EOF): got false, want true")
+	}
+	if err := WrapNoStack(New("x"), "y"); fmt.Sprintf("%+v", err) == "y: x" {
+		t.Errorf("WrapNoStack(New(\"x\"), \"y\"): %%+v lost the stack trace of its cause")
+	}
+}
+
+type countingError struct{ n *int }
+
+func (e countingError) Error() string {
+	*e.n++
+	return "counted"
+}
+
+func TestWrapErrorMemoized(t *testing.T) {
+	n := 0
+	err := Wrap(Wrap(countingError{&n}, "inner"), "outer")
+	for i := 0; i < 3; i++ {
+		if got, want := err.Error(), "outer: inner: counted"; got != want {
+			t.Errorf("Error(): got %q, want %q", got, want)
+		}
+	}
+	if got, want := fmt.Sprintf("%v", err), "outer: inner: counted"; got != want {
+		t.Errorf("%%v: got %q, want %q", got, want)
+	}
+	if n != 1 {
+		t.Errorf("Error(): cause message computed %d times, want 1", n)
+	}
+}
+
+type foreignStack struct{ error }
+
+func (foreignStack) StackTrace() StackTrace { return nil }
+
+func TestHasStack(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{NewNoStack("x"), false},
+		{WrapNoStack(io.EOF, "x"), false},
+		{New("x"), true},
+		{Errorf("x"), true},
+		{WithStack(io.EOF), true},
+		{Wrap(io.EOF, "x"), true},
+		{WrapNoStack(New("x"), "y"), true},
+		{fmt.Errorf("x: %w", New("y")), true},
+		{WrapNoStack(fmt.Errorf("x: %w", foreignStack{io.EOF}), "y"), true},
+		{WrapNoStack(fmt.Errorf("x: %w", io.EOF), "y"), false},
+	}
+	for i, tt := range tests {
+		if got := HasStack(tt.err); got != tt.want {
+			t.Errorf("test %d: HasStack(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWithMessageChain(t *testing.T) {
+	err := WithMessage(Wrap(WithMessagef(New("inner"), "first %d", 1), "second"), "third")
+	if got, want := err.Error(), "third: second: first 1: inner"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	inner := Unwrap(err)
+	if got, want := inner.Error(), "second: first 1: inner"; got != want {
+		t.Errorf("Unwrap(err).Error(): got %q, want %q", got, want)
+	}
+	if got, want := WithMessage(fmt.Errorf("wrapped: %w", io.EOF), "outer").Error(), "outer: wrapped: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+}
+
+func TestErrorfWrappedStack(t *testing.T) {
+	inner := New("inner")
+	err := Errorf("outer: %w", inner)
+	if got, want := err.Error(), "outer: inner"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	got, _ := stackTraceOf(err)
+	want, _ := stackTraceOf(inner)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Errorf with a stacked %%w operand: got stack trace %v, want %v", got, want)
+	}
+	if !Is(err, inner) {
+		t.Errorf("Is(%v, %v): got false, want true", err, inner)
+	}
+	if st, _ := stackTraceOf(Errorf("outer: %w", io.EOF)); len(st) == 0 {
+		t.Errorf("Errorf with an unstacked %%w operand: no stack trace recorded")
+	}
+}
+
+func TestWrapIf(t *testing.T) {
+	err := WrapIf(true, io.EOF, "read")
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("WrapIf(true): got %q, want %q", got, want)
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestWrapIf" {
+		t.Errorf("WrapIf(true): got stack trace %v", st)
+	}
+	if got := WrapIf(false, io.EOF, "read"); got != io.EOF {
+		t.Errorf("WrapIf(false): got %v, want io.EOF", got)
+	}
+	if WrapIf(true, nil, "read") != nil {
+		t.Errorf("WrapIf(true, nil): got non-nil error")
+	}
+
+	if got := WrapUnless(io.EOF, io.EOF, "read"); got != io.EOF {
+		t.Errorf("WrapUnless(io.EOF, io.EOF): got %v, want io.EOF", got)
+	}
+	err = WrapUnless(io.ErrUnexpectedEOF, io.EOF, "read")
+	if got, want := err.Error(), "read: unexpected EOF"; got != want {
+		t.Errorf("WrapUnless: got %q, want %q", got, want)
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestWrapIf" {
+		t.Errorf("WrapUnless: got stack trace %v", st)
+	}
+	if WrapUnless(nil, io.EOF, "read") != nil {
+		t.Errorf("WrapUnless(nil): got non-nil error")
+	}
+}
+
+func TestRootCause(t *testing.T) {
+	group := &GroupError{Errors: []error{io.EOF}}
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},
+		{io.EOF, io.EOF},
+		{Wrap(io.EOF, "read"), io.EOF},
+		{Wrap(Wrap(WithFields(io.EOF, Label("k", 1)), "read"), "load"), io.EOF},
+		{fmt.Errorf("load: %w", Wrap(io.EOF, "read")), io.EOF},
+		{Wrap(group, "wait"), group},
+	}
+	for i, tt := range tests {
+		if got := RootCause(tt.err); got != tt.want {
+			t.Errorf("test %d: RootCause(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+	err := Wrap(io.EOF, "read")
+	if Cause(err) == io.EOF {
+		t.Errorf("Cause(%v): got the root cause", err)
+	}
+}
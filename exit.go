@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// WithExitCode annotates err with the status code a program failing with
+// err exits with, as reported by ExitCode, without changing its message.
+// If err is nil, WithExitCode returns nil.
+func WithExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &withExitCode{err, code}
+}
+
+// ExitCode returns the status code a program failing with err should exit
+// with: the code of the first error in err's chain that has one, 1 if none
+// has, or if the code is negative, and 0 if err is nil. An error has a code
+// if it was annotated with WithExitCode or if it has an ExitCode() int
+// method, as the errors of package os/exec have.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code := 1
+	find(err, func(err error) bool {
+		e, ok := err.(interface{ ExitCode() int })
+		if ok {
+			code = e.ExitCode()
+		}
+		return ok
+	})
+	if code < 0 {
+		return 1
+	}
+	return code
+}
+
+type withExitCode struct {
+	error
+	code int
+}
+
+func (w *withExitCode) ExitCode() int { return w.code }
+
+func (w *withExitCode) Cause() error { return w.error }
+
+func (w *withExitCode) Unwrap() error { return w.error }
+
+func (w *withExitCode) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withExitCode) own() {}
+
+// stderr and exit are replaced by the tests of Fatal.
+var (
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
+)
+
+// Fatal ends a command line program failing with err: it prints err to the
+// standard error with a Renderer showing its hints, reports it to the
+// function set by SetReporter, if any, and exits with the code returned by
+// ExitCode. It is meant as the epilogue of the main function:
+//
+//	func main() {
+//		errors.Fatal(run())
+//	}
+//
+// The output is coloured if ShouldColor reports the standard error should
+// be, and is wrapped at the width set by the COLUMNS environment variable.
+// Setting the GOTRACEBACK environment variable to "all", "system" or
+// "crash", as when debugging panics, also prints the fields and stack trace
+// of err. If err is nil, Fatal returns.
+func Fatal(err error) {
+	if err == nil {
+		return
+	}
+	r := fatalRenderer(stderr)
+	fmt.Fprintln(stderr, r.Render(err))
+	if report := loadConfig().reporter; report != nil {
+		report(err)
+	}
+	exit(ExitCode(err))
+}
+
+// fatalRenderer returns the Renderer Fatal renders errors written to w
+// with, as set by the environment.
+func fatalRenderer(w io.Writer) Renderer {
+	r := Renderer{ShowHints: true, Color: ShouldColor(w)}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		r.MaxWidth = n
+	}
+	switch os.Getenv("GOTRACEBACK") {
+	case "all", "system", "crash":
+		r.ShowFields = true
+		r.FrameLimit = -1
+	}
+	return r
+}
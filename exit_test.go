@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 3").Run()
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{io.EOF, 1},
+		{WithExitCode(io.EOF, 2), 2},
+		{Wrap(WithExitCode(io.EOF, 64), "read"), 64},
+		{WithExitCode(io.EOF, -1), 1},
+		{Wrap(exitErr, "run"), 3},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v): got %d, want %d", tt.err, got, tt.want)
+		}
+	}
+	if WithExitCode(nil, 2) != nil {
+		t.Errorf("WithExitCode(nil): got non-nil error")
+	}
+	if got, want := WithExitCode(io.EOF, 2).Error(), "EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	var b bytes.Buffer
+	code := -1
+	defer func(w io.Writer, fn func(int)) { stderr, exit = w, fn }(stderr, exit)
+	stderr, exit = &b, func(c int) { code = c }
+	var reported error
+	defer SetReporter(nil)
+	SetReporter(func(err error) { reported = err })
+
+	Fatal(nil)
+	if code != -1 || b.Len() != 0 || reported != nil {
+		t.Fatalf("Fatal(nil): exited with %d, printed %q", code, b.String())
+	}
+
+	defer setenv("GOTRACEBACK", "")()
+	err := WithExitCode(WithHint(Wrap(io.EOF, "read config"), "check the file"), 2)
+	Fatal(err)
+	if code != 2 {
+		t.Errorf("exit code: got %d, want 2", code)
+	}
+	if reported != err {
+		t.Errorf("reported: got %v, want %v", reported, err)
+	}
+	if got, want := b.String(), "read config\n  caused by: EOF\n  hint: check the file\n"; got != want {
+		t.Errorf("output: got %q, want %q", got, want)
+	}
+
+	b.Reset()
+	setenv("GOTRACEBACK", "all")
+	Fatal(err)
+	if got := b.String(); !strings.Contains(got, "\n  at github.com/pkg/errors.TestFatal (") {
+		t.Errorf("output with GOTRACEBACK=all: got %q, want the stack trace", got)
+	}
+}
@@ -0,0 +1,70 @@
+package errors
+
+import "fmt"
+
+// Field is a key-value pair attached to an error, recording structured
+// context, such as the ID of a request, that does not belong in its
+// message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Label returns the Field of key and value.
+func Label(key string, value interface{}) Field {
+	return Field{key, value}
+}
+
+// WithFields annotates err with fields without changing its message.
+// If err is nil, WithFields returns nil.
+func WithFields(err error, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+	return &withFields{err, append([]Field(nil), fields...)}
+}
+
+// Fields returns the fields attached to the errors of err's chain with
+// WithFields or Detach, outermost first. A key can appear several times when
+// different layers of the chain set it.
+func Fields(err error) []Field {
+	var fields []Field
+	find(err, func(err error) bool {
+		switch w := err.(type) {
+		case *withFields:
+			fields = append(fields, w.fields...)
+		case *withDetached:
+			fields = append(fields, w.fields...)
+		}
+		return false
+	})
+	return fields
+}
+
+type withFields struct {
+	error
+	fields []Field
+}
+
+func (w *withFields) Cause() error { return w.error }
+
+func (w *withFields) Unwrap() error { return w.error }
+
+func (w *withFields) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withFields) own() {}
+
+// withDetached holds the snapshot of the fields of a context taken by
+// Detach.
+type withDetached struct {
+	error
+	fields []Field
+}
+
+func (w *withDetached) Cause() error { return w.error }
+
+func (w *withDetached) Unwrap() error { return w.error }
+
+func (w *withDetached) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withDetached) own() {}
@@ -0,0 +1,25 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	err := Wrap(WithFields(io.EOF, Label("file", "a.txt"), Label("offset", 42)), "read")
+	err = WithFields(err, Label("request", "r1"))
+	want := []Field{{"request", "r1"}, {"file", "a.txt"}, {"offset", 42}}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields: got %v, want %v", got, want)
+	}
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if Fields(io.EOF) != nil || WithFields(nil, Label("k", "v")) != nil {
+		t.Errorf("Fields: got fields for an error without any")
+	}
+	if got := Depth(err); got != 2 {
+		t.Errorf("Depth(%v): got %d, want 2", err, got)
+	}
+}
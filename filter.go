@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// visible returns the frames of st accepted by the filter installed with
+// SetStackFilter. st is returned as is if no filter is installed.
+func (st StackTrace) visible() StackTrace {
+	keep := loadConfig().stackFilter
+	if keep == nil {
+		return st
+	}
+	var out StackTrace
+	for _, f := range st {
+		if keep(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// MarshalJSON encodes the frames of st accepted by the filter installed
+// with SetStackFilter as a JSON array of their text encodings; see
+// Frame.MarshalText.
+func (st StackTrace) MarshalJSON() ([]byte, error) {
+	visible := st.visible()
+	if visible == nil && st != nil {
+		visible = StackTrace{}
+	}
+	return json.Marshal([]Frame(visible))
+}
+
+// SetStackFilter installs a filter that decides which frames are printed
+// when a stack trace is formatted, and encoded when it is marshalled to
+// JSON. Frames for which keep returns false are omitted from the output;
+// the StackTrace values returned by errors are not affected. A nil keep
+// removes the filter.
+//
+// SetStackFilter is typically called once during program initialisation,
+// for example
+//
+//	errors.SetStackFilter(errors.ExcludePackages("runtime", "testing"))
+func SetStackFilter(keep func(Frame) bool) {
+	updateConfig(func(c *config) { c.stackFilter = keep })
+}
+
+// ExcludePackages returns a filter for use with SetStackFilter that rejects
+// frames whose function belongs to one of the given packages or to a package
+// nested below one of them. Packages are import paths such as "runtime" or
+// "github.com/vendor/lib".
+func ExcludePackages(pkgs ...string) func(Frame) bool {
+	return func(f Frame) bool {
+		p := pkgname(f.Name())
+		for _, pkg := range pkgs {
+			if p == pkg || strings.HasPrefix(p, pkg+"/") {
+				return false
+			}
+		}
+		return true
+	}
+}
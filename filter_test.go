@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPkgname(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"", ""},
+		{"runtime.main", "runtime"},
+		{"github.com/pkg/errors.funcname", "github.com/pkg/errors"},
+		{"main.(*R).Write", "main"},
+		{"gopkg.in/yaml.v2.Unmarshal", "gopkg.in/yaml"},
+		{"funcname", "funcname"},
+	}
+
+	for _, tt := range tests {
+		got := pkgname(tt.name)
+		if got != tt.want {
+			t.Errorf("pkgname(%q): want: %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSetStackFilter(t *testing.T) {
+	defer SetStackFilter(nil)
+
+	err := New("filtered")
+	SetStackFilter(ExcludePackages("runtime", "testing"))
+	got := fmt.Sprintf("%+v", err)
+	for _, pkg := range []string{"\nruntime.", "\ntesting."} {
+		if strings.Contains(got, pkg) {
+			t.Errorf("%%+v with filter: got %q, want no %q frames", got, pkg)
+		}
+	}
+	if !strings.Contains(got, "github.com/pkg/errors.TestSetStackFilter") {
+		t.Errorf("%%+v with filter: got %q, want the caller frame", got)
+	}
+
+	var st interface{ StackTrace() StackTrace }
+	if !As(err, &st) {
+		t.Fatalf("expected %#v to carry a StackTrace", err)
+	}
+	if got := fmt.Sprintf("%s", st.StackTrace()); got != "[filter_test.go]" {
+		t.Errorf("%%s of StackTrace with filter: got %q, want %q", got, "[filter_test.go]")
+	}
+	if len(st.StackTrace()) < 2 {
+		t.Errorf("StackTrace(): got %d frames, the filter must not drop frames", len(st.StackTrace()))
+	}
+
+	b, jerr := json.Marshal(st.StackTrace())
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var frames []string
+	if jerr := json.Unmarshal(b, &frames); jerr != nil {
+		t.Fatal(jerr)
+	}
+	if len(frames) != 1 || !strings.HasPrefix(frames[0], "github.com/pkg/errors.TestSetStackFilter ") {
+		t.Errorf("json.Marshal of StackTrace with filter: got %s, want the caller frame only", b)
+	}
+
+	SetStackFilter(nil)
+	if b, _ := json.Marshal(st.StackTrace()); !strings.Contains(string(b), "testing.") {
+		t.Errorf("json.Marshal of StackTrace without filter: got %s, want testing frames", b)
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "\ntesting.") {
+		t.Errorf("%%+v without filter: got %q, want testing frames", got)
+	}
+}
@@ -0,0 +1,171 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FormatConfig sets the layout of errors printed with %+v; see SetFormat.
+// The zero FormatConfig is the default layout.
+type FormatConfig struct {
+	// Order is the order the messages of the layers of a chain are
+	// printed in.
+	Order Order
+
+	// FrameStyle is how the frames of stack traces are printed.
+	FrameStyle FrameStyle
+
+	// Separator is printed between the messages of the layers of a
+	// chain, instead of ": ".
+	Separator string
+
+	// MessageLimit, if positive, is the length in bytes the messages of
+	// the layers of a chain are truncated to, such as those embedding a
+	// whole SQL statement. A truncated message is followed by the length
+	// and a hash of the part omitted, as in "…(+1234 bytes #9f86d081)", so
+	// that errors differing only in that part can still be told apart.
+	MessageLimit int
+
+	// HideFields omits the fields of errors, which are otherwise printed
+	// on a line of their own between the message and the stack trace,
+	// sorted by key, as in
+	//
+	//	fields: request=42 user="Jane Doe"
+	//
+	// Keys and values are quoted as Go strings when empty or when they
+	// contain spaces, quotes, equal signs or characters that are not
+	// printable, such as newlines.
+	HideFields bool
+}
+
+// Order is the order the layers of a chain are printed in.
+type Order int
+
+// The orders of layers.
+const (
+	// DefaultOrder is the order set with SetFormat, OutermostFirst
+	// unless changed.
+	DefaultOrder Order = iota
+
+	// OutermostFirst prints the message of the outermost layer first
+	// and the root cause last, as Error does.
+	OutermostFirst
+
+	// InnermostFirst prints the root cause first, followed by the
+	// messages of the layers wrapping it.
+	InnermostFirst
+)
+
+// FrameStyle is how the frames of stack traces are printed.
+type FrameStyle int
+
+// The styles of frames.
+const (
+	// TwoLineFrames prints each frame as its function followed by its
+	// file and line on a line of their own, indented by a tab.
+	TwoLineFrames FrameStyle = iota
+
+	// OneLineFrames prints each frame as its function, file and line
+	// on a single line.
+	OneLineFrames
+
+	// NoFrames prints no stack trace at all.
+	NoFrames
+)
+
+// SetFormat sets the layout of the errors of this package printed with
+// %+v, and rendered by Render, for the whole process, for organisations
+// expecting the root cause first for example:
+//
+//	errors.SetFormat(errors.FormatConfig{
+//		Order:      errors.InnermostFirst,
+//		FrameStyle: errors.OneLineFrames,
+//		Separator:  " <- ",
+//	})
+//
+// Wrap(io.EOF, "read") then prints as "EOF <- read", followed by its stack
+// trace, one frame per line. The frame style also applies to the stack
+// traces printed with %+v, and the frame formatter set with
+// SetFrameFormatter overrides it. Error and the other verbs are not
+// affected.
+func SetFormat(f FormatConfig) {
+	updateConfig(func(c *config) { c.format = f })
+}
+
+// message returns the message of err as printed with f: the messages of
+// the layers of its chain in the order of f, joined by its separator.
+func (f FormatConfig) message(err error) string {
+	if f.Order != InnermostFirst && f.Separator == "" && f.MessageLimit <= 0 {
+		return err.Error()
+	}
+	msgs := Messages(err)
+	if f.MessageLimit > 0 {
+		for i, msg := range msgs {
+			msgs[i] = truncateMessage(msg, f.MessageLimit)
+		}
+	}
+	if f.Order == InnermostFirst {
+		for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+			msgs[i], msgs[j] = msgs[j], msgs[i]
+		}
+	}
+	sep := f.Separator
+	if sep == "" {
+		sep = ": "
+	}
+	return strings.Join(msgs, sep)
+}
+
+// truncateMessage returns msg truncated to at most n bytes, without
+// splitting a character, followed by the length and a hash of the part
+// omitted, or msg if it is not longer than n bytes.
+func truncateMessage(msg string, n int) string {
+	if len(msg) <= n {
+		return msg
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	h := fnv.New32a()
+	h.Write([]byte(msg[cut:]))
+	return fmt.Sprintf("%s…(+%d bytes #%08x)", msg[:cut], len(msg)-cut, h.Sum32())
+}
+
+// writeFields writes the fields of err, if any, on a line of their own
+// preceded by a newline, as documented by FormatConfig.HideFields. Fields of
+// a same key are printed outermost first, and keys and values with %v.
+func writeFields(w io.Writer, err error) {
+	fields := Fields(err)
+	if len(fields) == 0 {
+		return
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	io.WriteString(w, "\nfields:")
+	for _, f := range fields {
+		io.WriteString(w, " ")
+		io.WriteString(w, quoteField(f.Key))
+		io.WriteString(w, "=")
+		io.WriteString(w, quoteField(fmt.Sprint(f.Value)))
+	}
+}
+
+// quoteField returns s quoted as a Go string if it is empty or contains
+// characters that would make a key=value pair ambiguous.
+func quoteField(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -558,3 +558,87 @@ func testGenericRecursive(t *testing.T, beforeErr error, beforeWant []string, li
 		}
 	}
 }
+
+func TestSetFormat(t *testing.T) {
+	defer SetFormat(FormatConfig{})
+
+	err := Wrap(WithMessage(io.EOF, "read"), "load")
+	st, _ := stackTraceOf(err)
+	tests := []struct {
+		f      FormatConfig
+		prefix string
+		frame  string // the first frame printed, if any
+	}{
+		{FormatConfig{}, "load: read: EOF\n", fmt.Sprintf("\n%+v", st[0])},
+		{FormatConfig{Order: OutermostFirst}, "load: read: EOF\n", fmt.Sprintf("\n%+v", st[0])},
+		{FormatConfig{Order: InnermostFirst}, "EOF: read: load\n", fmt.Sprintf("\n%+v", st[0])},
+		{FormatConfig{Separator: " → "}, "load → read → EOF\n", fmt.Sprintf("\n%+v", st[0])},
+		{FormatConfig{Order: InnermostFirst, FrameStyle: OneLineFrames, Separator: " <- "}, "EOF <- read <- load\n",
+			fmt.Sprintf("\n%s %s:%d\n", st[0].Name(), st[0].path(), st[0].Line())},
+		{FormatConfig{FrameStyle: NoFrames}, "load: read: EOF", ""},
+	}
+	for i, tt := range tests {
+		SetFormat(tt.f)
+		got := fmt.Sprintf("%+v", err)
+		if !strings.HasPrefix(got, tt.prefix) && got != tt.prefix {
+			t.Errorf("test %d: got %q, want it to start with %q", i+1, got, tt.prefix)
+		}
+		if tt.frame == "" && got != tt.prefix {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.prefix)
+		}
+		if tt.frame != "" && !strings.Contains(got, tt.frame) {
+			t.Errorf("test %d: got %q, want it to contain %q", i+1, got, tt.frame)
+		}
+		if got := err.Error(); got != "load: read: EOF" {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, "load: read: EOF")
+		}
+		if got, want := Render(err, RenderOptions{}), fmt.Sprintf("%+v", err); got != want {
+			t.Errorf("test %d: Render: got %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+func TestFormatMessageLimit(t *testing.T) {
+	defer SetFormat(FormatConfig{})
+
+	SetFormat(FormatConfig{MessageLimit: 8, FrameStyle: NoFrames})
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{WithMessage(io.EOF, "short"), "short: EOF"},
+		{WithMessage(io.EOF, "select * from users"), "select *…(+11 bytes #33fa48ff): EOF"},
+		{WithMessage(io.EOF, "select * from items"), "select *…(+11 bytes #555217e3): EOF"},
+		{WithMessage(io.EOF, "ééééé"), "éééé…(+2 bytes #1e9de8c1): EOF"},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf("%+v", tt.err); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+	if got := WithMessage(io.EOF, "select * from users").Error(); got != "select * from users: EOF" {
+		t.Errorf("Error(): got %q, want the whole message", got)
+	}
+}
+
+func TestFormatFields(t *testing.T) {
+	defer SetFormat(FormatConfig{})
+
+	err := WithFields(WithMessage(WithFields(io.EOF, Label("b", 1), Label("a", "x")), "read"),
+		Label("user", "Jane Doe"), Label("b", 0), Label("q", `say "hi"`), Label("n", "a\nb"), Label("e", ""), Label("k=v", true))
+	SetFormat(FormatConfig{FrameStyle: NoFrames})
+	want := "read: EOF\nfields: a=x b=0 b=1 e=\"\" \"k=v\"=true n=\"a\\nb\" q=\"say \\\"hi\\\"\" user=\"Jane Doe\""
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v:\n got %q\nwant %q", got, want)
+	}
+	SetFormat(FormatConfig{FrameStyle: NoFrames, HideFields: true})
+	if got := fmt.Sprintf("%+v", err); got != "read: EOF" {
+		t.Errorf("HideFields: got %q, want %q", got, "read: EOF")
+	}
+
+	SetFormat(FormatConfig{})
+	err = WithFields(Wrap(io.EOF, "read"), Label("a", 1))
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "read: EOF\nfields: a=1\ngithub.com/pkg/errors.TestFormatFields\n") {
+		t.Errorf("%%+v: got %q, want the fields between the message and the stack trace", got)
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+import "io"
+
+// SetFrameFormatter replaces the rendering of the frames of stack traces.
+// format is called with verbose set for each frame printed by %+v, and
+// with verbose unset for each frame of a StackTrace printed by %v. It must
+// not write the newline that separates frames in the verbose form; the
+// application frame marker and source context, if enabled, are still
+// written around its output. A nil format restores the default rendering,
+// which is equivalent to
+//
+//	func(w io.Writer, f errors.Frame, verbose bool) {
+//		if verbose {
+//			fmt.Fprintf(w, "%+v", f)
+//		} else {
+//			fmt.Fprintf(w, "%v", f)
+//		}
+//	}
+func SetFrameFormatter(format func(w io.Writer, f Frame, verbose bool)) {
+	updateConfig(func(c *config) { c.frameFormatter = format })
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetFrameFormatter(t *testing.T) {
+	defer SetFrameFormatter(nil)
+
+	SetFrameFormatter(func(w io.Writer, f Frame, verbose bool) {
+		if verbose {
+			fmt.Fprintf(w, "%n (%s:%d)", f, f, f)
+		} else {
+			fmt.Fprintf(w, "<%n>", f)
+		}
+	})
+
+	got := fmt.Sprintf("%+v", New("custom"))
+	want := "custom\nTestSetFrameFormatter (formatter_test.go:21)\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("%%+v: got %q, want prefix %q", got, want)
+	}
+
+	st := StackTrace{initpc, initpc}
+	if got, want := fmt.Sprintf("%v", st), "[<init> <init>]"; got != want {
+		t.Errorf("%%v of StackTrace: got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%s", st), "[stack_test.go stack_test.go]"; got != want {
+		t.Errorf("%%s of StackTrace: got %q, want %q", got, want)
+	}
+
+	SetFrameFormatter(nil)
+	testFormatRegexp(t, 0, New("default"), "%+v", "default\ngithub.com/pkg/errors.TestSetFrameFormatter\n\t.+/formatter_test.go:36")
+}
@@ -0,0 +1,138 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+// SetGoroutineCapture enables recording the ID of the capturing goroutine
+// along with every stack trace captured by this package. The ID is printed
+// by %+v as a "goroutine 113" line preceding the stack trace, which makes it
+// possible to match an error against a goroutine dump taken around the same
+// time. Capturing the ID costs about as much as capturing a stack trace;
+// it is disabled by default.
+func SetGoroutineCapture(enabled bool) {
+	updateConfig(func(c *config) { c.goroutineCapture = enabled })
+}
+
+// currentGoroutine returns the ID of the calling goroutine if goroutine
+// capture is enabled, and 0 otherwise.
+func currentGoroutine() int64 {
+	if !loadConfig().goroutineCapture {
+		return 0
+	}
+	return goroutineID()
+}
+
+// goroutineID returns the ID of the calling goroutine, as parsed from the
+// "goroutine 113 [running]:" header of its stack dump.
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
+
+// GoroutineID returns the ID of the goroutine that captured the stack trace
+// of the first error in err's chain that recorded one. Goroutine IDs are
+// only recorded while enabled with SetGoroutineCapture.
+func GoroutineID(err error) (int64, bool) {
+	var g interface {
+		goroutine() int64
+	}
+	for n := 0; err != nil && n < maxChainDepth; n++ {
+		if As(err, &g) {
+			if id := g.goroutine(); id != 0 {
+				return id, true
+			}
+		} else {
+			break
+		}
+		err = Unwrap(g.(error))
+	}
+	return 0, false
+}
+
+func (w *withStack) goroutine() int64 { return w.goid }
+
+// writeGoroutine writes the line identifying goroutine id, preceded by a
+// newline.
+func writeGoroutine(w io.Writer, id int64) {
+	io.WriteString(w, "\ngoroutine ")
+	io.WriteString(w, strconv.FormatInt(id, 10))
+}
+
+// maxGoroutineDump bounds the size of the dumps recorded by
+// WithGoroutineDump.
+const maxGoroutineDump = 1 << 20
+
+// WithGoroutineDump annotates err with a dump of the stacks of all
+// goroutines, as reported by runtime.Stack, taken at the point
+// WithGoroutineDump is called. Dumps larger than 1 MiB are truncated.
+// The dump is printed after the error by %+v and can be retrieved with
+// GoroutineDump. Taking the dump stops the world; WithGoroutineDump is
+// meant for errors that terminate the process.
+// If err is nil, WithGoroutineDump returns nil.
+func WithGoroutineDump(err error) error {
+	if err == nil {
+		return nil
+	}
+	buf := make([]byte, maxGoroutineDump)
+	n := runtime.Stack(buf, true)
+	// Copy the dump so that the error does not retain the whole buffer.
+	const marker = "\n… truncated"
+	var dump []byte
+	if n == len(buf) {
+		dump = make([]byte, n, n+len(marker))
+		copy(dump, buf)
+		dump = append(dump, marker...)
+	} else {
+		dump = make([]byte, n)
+		copy(dump, buf)
+	}
+	return &withGoroutineDump{err, dump}
+}
+
+// GoroutineDump returns the goroutine dump recorded by WithGoroutineDump
+// in err's chain, if any.
+func GoroutineDump(err error) ([]byte, bool) {
+	var d *withGoroutineDump
+	if !As(err, &d) {
+		return nil, false
+	}
+	return d.dump, true
+}
+
+type withGoroutineDump struct {
+	error
+	dump []byte
+}
+
+func (w *withGoroutineDump) Cause() error { return w.error }
+
+func (w *withGoroutineDump) Unwrap() error { return w.error }
+
+func (*withGoroutineDump) own() {}
+
+func (w *withGoroutineDump) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v\n\n", w.error)
+			s.Write(w.dump)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	}
+}
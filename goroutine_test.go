@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestGoroutineID(t *testing.T) {
+	if id, ok := GoroutineID(New("not recorded")); ok {
+		t.Errorf("GoroutineID() without capture: got %d, want none", id)
+	}
+
+	defer SetGoroutineCapture(false)
+	SetGoroutineCapture(true)
+
+	want := goroutineID()
+	if want <= 0 {
+		t.Fatalf("goroutineID(): got %d", want)
+	}
+	done := make(chan error)
+	go func() { done <- New("other goroutine") }()
+	other := <-done
+
+	otherID, ok := GoroutineID(other)
+	if !ok || otherID == want {
+		t.Fatalf("GoroutineID() of error from another goroutine: got %d, %v", otherID, ok)
+	}
+
+	tests := []struct {
+		err    error
+		want   int64
+		wantOK bool
+	}{
+		{New("recorded"), want, true},
+		{Errorf("recorded"), want, true},
+		{WithStack(io.EOF), want, true},
+		{Wrap(other, "wrapped here"), otherID, true},
+		{io.EOF, 0, false},
+	}
+	for i, tt := range tests {
+		id, ok := GoroutineID(tt.err)
+		if id != tt.want || ok != tt.wantOK {
+			t.Errorf("test %d: GoroutineID(): got %d, %v, want %d, %v", i+1, id, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	got := fmt.Sprintf("%+v", New("recorded"))
+	if prefix := fmt.Sprintf("recorded\ngoroutine %d\ngithub.com/pkg/errors.TestGoroutineID\n", want); !strings.HasPrefix(got, prefix) {
+		t.Errorf("%%+v: got %q, want prefix %q", got, prefix)
+	}
+}
+
+func TestWithGoroutineDump(t *testing.T) {
+	if got := WithGoroutineDump(nil); got != nil {
+		t.Errorf("WithGoroutineDump(nil): got %#v, want nil", got)
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+
+	err := WithGoroutineDump(io.EOF)
+	if got := err.Error(); got != "EOF" {
+		t.Errorf("Error(): got %q, want %q", got, "EOF")
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(err, io.EOF): got false, want true")
+	}
+	dump, ok := GoroutineDump(Wrap(err, "fatal"))
+	if !ok {
+		t.Fatalf("GoroutineDump(): got no dump")
+	}
+	if n := strings.Count(string(dump), "\ngoroutine "); n < 1 {
+		t.Errorf("GoroutineDump(): got %q, want every goroutine", dump)
+	}
+	if !strings.Contains(string(dump), "TestWithGoroutineDump.func1") {
+		t.Errorf("GoroutineDump(): got %q, want the blocked goroutine", dump)
+	}
+	if cap(dump) != len(dump) {
+		t.Errorf("GoroutineDump(): got a capacity of %d for %d bytes, want the buffer released", cap(dump), len(dump))
+	}
+
+	got := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(got, "EOF\n\ngoroutine ") || !strings.HasSuffix(got, string(dump)) {
+		t.Errorf("%%+v: got %q, want the error followed by the dump", got)
+	}
+	if _, ok := GoroutineDump(io.EOF); ok {
+		t.Errorf("GoroutineDump(io.EOF): got a dump, want none")
+	}
+}
@@ -0,0 +1,18 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// writeGoSyntax writes err in the %#v layout: its type, message, the type
+// of the error it wraps and the number of frames of its stack trace, such
+// as
+//
+//	*errors.withMessage{Message:"read: EOF", Cause:*errors.withStack, Frames:3}
+func writeGoSyntax(w io.Writer, err error) {
+	st, _ := stackTraceOf(err)
+	fmt.Fprintf(w, "%T{Message:%s, Cause:%T, Frames:%s}",
+		err, strconv.Quote(err.Error()), Unwrap(err), strconv.Itoa(len(st)))
+}
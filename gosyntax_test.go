@@ -0,0 +1,26 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestFormatGoSyntax(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	st, _ := stackTraceOf(err)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err, fmt.Sprintf(`*errors.withMessage{Message:"read: EOF", Cause:*errors.withStack, Frames:%d}`, len(st))},
+		{WithMessage(io.EOF, "read"), `*errors.withMessage{Message:"read: EOF", Cause:*errors.errorString, Frames:0}`},
+		{NewNoStack("new"), `errors.formatted{Message:"new", Cause:*errors.errorString, Frames:0}`},
+		{WithCode(NewNoStack(`"quoted"`), "E1"), `*errors.withCode{Message:"\"quoted\"", Cause:errors.formatted, Frames:0}`},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf("%#v", tt.err); got != tt.want {
+			t.Errorf("test %d: got %s, want %s", i+1, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Group runs functions in goroutines and collects their errors. Like Go,
+// it converts panics into errors and annotates errors without a stack
+// trace with that of the point the goroutine was started. The zero Group
+// is ready to use; a Group must not be copied after first use.
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs fn in a new goroutine. If fn fails or panics, its error is
+// annotated with labels, retrieved with Fields, so that the failures of a
+// group can be attributed to the goroutine they happened in:
+//
+//	for shard := range shards {
+//		g.Go(process(shard), errors.Label("shard", shard))
+//	}
+func (g *Group) Go(fn func() error, labels ...Field) {
+	l := launchSite()
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := l.run(fn); err != nil {
+			if len(labels) > 0 {
+				err = &withFields{err, labels}
+			}
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait waits for the goroutines started by Go to return. It returns nil if
+// none failed, the error of the goroutine that failed if only one did, and
+// a *GroupError holding all of them otherwise.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch len(g.errs) {
+	case 0:
+		return nil
+	case 1:
+		return g.errs[0]
+	}
+	return &GroupError{append([]error(nil), g.errs...)}
+}
+
+// GroupError holds the errors of the goroutines of a Group that failed, in
+// the order they failed.
+type GroupError struct {
+	Errors []error
+}
+
+func (e *GroupError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of e, for Is and As.
+func (e *GroupError) Unwrap() []error { return e.Errors }
+
+// Format formats e according to the fmt.Formatter interface. %+v prints
+// each error of e with %+v, separated by blank lines.
+func (e *GroupError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for i, err := range e.Errors {
+				if i > 0 {
+					io.WriteString(s, "\n\n")
+				}
+				fmt.Fprintf(s, "%+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	if err := g.Wait(); err != nil {
+		t.Errorf("empty Group: got %v, want nil", err)
+	}
+
+	g.Go(func() error { return nil })
+	g.Go(func() error { return io.EOF }, Label("shard", 7))
+	err := g.Wait()
+	if !Is(err, io.EOF) {
+		t.Fatalf("Wait: got %v, want io.EOF", err)
+	}
+	if got, want := Fields(err), []Field{{"shard", 7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields: got %v, want %v", got, want)
+	}
+	if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Name() != "github.com/pkg/errors.TestGroup" {
+		t.Errorf("Wait: got stack trace %v, want the launch site", st)
+	}
+
+	g = Group{}
+	g.Go(func() error { return io.EOF }, Label("shard", 1))
+	g.Go(func() error { panic("boom") }, Label("shard", 2))
+	err = g.Wait()
+	var ge *GroupError
+	if !As(err, &ge) || len(ge.Errors) != 2 {
+		t.Fatalf("Wait: got %v, want a *GroupError of 2 errors", err)
+	}
+	if !Is(err, io.EOF) || !IsPanic(err) {
+		t.Errorf("Wait: got %v, want io.EOF and a panic", err)
+	}
+	for _, err := range ge.Errors {
+		shard := Fields(err)[0].Value
+		if IsPanic(err) != (shard == 2) {
+			t.Errorf("error %v: got shard %v", err, shard)
+		}
+	}
+	if msg := err.Error(); !strings.Contains(msg, "EOF") || !strings.Contains(msg, "panic: boom") || !strings.Contains(msg, "; ") {
+		t.Errorf("Error(): got %q", msg)
+	}
+	if out := fmt.Sprintf("%+v", err); !strings.Contains(out, "\n\n") || !strings.Contains(out, "github.com/pkg/errors.TestGroup") {
+		t.Errorf("%%+v: got %q", out)
+	}
+}
@@ -0,0 +1,42 @@
+package errors
+
+import "fmt"
+
+// MarkHandled annotates err as handled, reported by WasHandled, without
+// changing its message. A layer that takes responsibility for an error it
+// still returns, by logging it or reporting it to the user for example,
+// marks it handled, so that middleware at the top of the stack can tell
+// the errors nobody handled, which deserve a higher severity:
+//
+//	if err := h.ServeHTTP(w, r); err != nil && !errors.WasHandled(err) {
+//		log.Printf("unhandled error: %+v", err)
+//	}
+//
+// If err is nil, MarkHandled returns nil.
+func MarkHandled(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withHandled{err}
+}
+
+// WasHandled reports whether an error in err's chain was marked handled
+// with MarkHandled.
+func WasHandled(err error) bool {
+	return find(err, func(err error) bool {
+		_, ok := err.(*withHandled)
+		return ok
+	})
+}
+
+type withHandled struct {
+	error
+}
+
+func (w *withHandled) Cause() error { return w.error }
+
+func (w *withHandled) Unwrap() error { return w.error }
+
+func (w *withHandled) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withHandled) own() {}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestMarkHandled(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	if WasHandled(err) {
+		t.Errorf("WasHandled(%v): got true", err)
+	}
+	handled := Wrap(MarkHandled(err), "serve")
+	if !WasHandled(handled) || !Is(handled, io.EOF) {
+		t.Errorf("WasHandled(%v): got false", handled)
+	}
+	if got, want := handled.Error(), "serve: read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", MarkHandled(err)), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if Depth(handled) != 3 {
+		t.Errorf("Depth(%v): got %d, want 3", handled, Depth(handled))
+	}
+	if !WasHandled(Clone(handled)) {
+		t.Errorf("Clone: lost the handled mark")
+	}
+	if MarkHandled(nil) != nil {
+		t.Errorf("MarkHandled(nil): got non-nil error")
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "fmt"
+
+// WithHint annotates err with hint, a suggestion telling the user how to
+// fix the error, such as "check that the file exists", without changing
+// its message. Hints are printed by Renderer when ShowHints is set.
+// If err is nil, WithHint returns nil.
+func WithHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &withHint{err, hint}
+}
+
+// Hints returns the hints of the errors of err's chain, outermost first.
+// An error has a hint if it was annotated with WithHint or if it has a
+// Hint() string method returning a non-empty hint.
+func Hints(err error) []string {
+	var hints []string
+	find(err, func(err error) bool {
+		if e, ok := err.(interface{ Hint() string }); ok && e.Hint() != "" {
+			hints = append(hints, e.Hint())
+		}
+		return false
+	})
+	return hints
+}
+
+type withHint struct {
+	error
+	hint string
+}
+
+func (w *withHint) Hint() string { return w.hint }
+
+func (w *withHint) Cause() error { return w.error }
+
+func (w *withHint) Unwrap() error { return w.error }
+
+func (w *withHint) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withHint) own() {}
@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+type hinted struct{ error }
+
+func (hinted) Hint() string { return "retry later" }
+
+func TestHints(t *testing.T) {
+	if WithHint(nil, "hint") != nil {
+		t.Errorf("WithHint(nil): got non-nil error")
+	}
+	err := WithHint(Wrap(hinted{io.EOF}, "read"), "check the file")
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := Hints(err), []string{"check the file", "retry later"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Hints: got %q, want %q", got, want)
+	}
+	if got := Hints(io.EOF); got != nil {
+		t.Errorf("Hints(io.EOF): got %q, want none", got)
+	}
+}
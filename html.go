@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// ToHTML returns a rendering of err for debug pages: the message of err,
+// followed by collapsible sections holding the messages of the layers of
+// its chain, its fields, and each of the distinct stack traces recorded
+// along its chain, outermost first. Frames link to their source when a URL
+// template is set with SetPathOptions; see Frame.URL. Everything printed is
+// escaped, so the result can be embedded as is in an HTML page. The
+// elements have classes, such as "errors-message" or "errors-app" for
+// application frames, for pages to style them. ToHTML returns the empty
+// string if err is nil.
+func ToHTML(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="errors-error">` + "\n")
+	b.WriteString(`<p class="errors-message"><strong>`)
+	b.WriteString(html.EscapeString(err.Error()))
+	b.WriteString("</strong></p>\n")
+
+	b.WriteString("<details open>\n<summary>Chain</summary>\n<ol class=\"errors-chain\">\n")
+	for _, msg := range Messages(err) {
+		b.WriteString("<li>")
+		b.WriteString(html.EscapeString(msg))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ol>\n</details>\n")
+
+	if fields := Fields(err); len(fields) > 0 {
+		b.WriteString("<details open>\n<summary>Fields</summary>\n<dl class=\"errors-fields\">\n")
+		for _, f := range fields {
+			b.WriteString("<dt>")
+			b.WriteString(html.EscapeString(f.Key))
+			b.WriteString("</dt><dd>")
+			b.WriteString(html.EscapeString(fmt.Sprint(f.Value)))
+			b.WriteString("</dd>\n")
+		}
+		b.WriteString("</dl>\n</details>\n")
+	}
+
+	stacks := stackTraces(err)
+	for i, st := range stacks {
+		b.WriteString("<details>\n<summary>Stack trace")
+		if len(stacks) > 1 {
+			b.WriteString(" " + strconv.Itoa(i+1) + " of " + strconv.Itoa(len(stacks)))
+		}
+		b.WriteString("</summary>\n<ol class=\"errors-stack\">\n")
+		for _, f := range st.visible() {
+			writeHTMLFrame(&b, f)
+		}
+		b.WriteString("</ol>\n</details>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// writeHTMLFrame writes f as an item of the list of the frames of a stack
+// trace.
+func writeHTMLFrame(b *strings.Builder, f Frame) {
+	if f.IsApplication() {
+		b.WriteString(`<li class="errors-app">`)
+	} else {
+		b.WriteString("<li>")
+	}
+	b.WriteString("<code>")
+	b.WriteString(html.EscapeString(f.Name()))
+	b.WriteString("</code><br>")
+	loc := html.EscapeString(f.path() + ":" + strconv.Itoa(f.Line()))
+	if url := f.URL(); url != "" {
+		b.WriteString(`<a href="`)
+		b.WriteString(html.EscapeString(url))
+		b.WriteString(`">`)
+		b.WriteString(loc)
+		b.WriteString("</a>")
+	} else {
+		b.WriteString(loc)
+	}
+	b.WriteString("</li>\n")
+}
+
+// stackTraces returns the stack traces recorded along err's chain,
+// outermost first. The stack trace truncated by TruncateStack is replaced
+// by its truncated version.
+func stackTraces(err error) []StackTrace {
+	var sts []StackTrace
+	skip := false
+	for n := 0; err != nil && n < maxChainDepth; n, err = n+1, Unwrap(err) {
+		s, ok := err.(interface{ StackTrace() StackTrace })
+		if !ok {
+			continue
+		}
+		if skip {
+			skip = false
+			continue
+		}
+		_, skip = err.(*truncated)
+		if st := s.StackTrace(); len(st) > 0 {
+			sts = append(sts, st)
+		}
+	}
+	return sts
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	if got := ToHTML(nil); got != "" {
+		t.Errorf("ToHTML(nil): got %q, want empty", got)
+	}
+
+	defer SetPathOptions(PathOptions{})
+	SetPathOptions(PathOptions{URLTemplate: "https://example.com/{file}?l={line}&x"})
+	inner := New("<inner>")
+	err := WithFields(Wrap(WithMessage(inner, "read & parse"), "load"), Label("<key>", `"v"`))
+	st, _ := stackTraceOf(inner)
+	got := ToHTML(err)
+	for _, want := range []string{
+		`<p class="errors-message"><strong>load: read &amp; parse: &lt;inner&gt;</strong></p>`,
+		"<li>load</li>\n<li>read &amp; parse</li>\n<li>&lt;inner&gt;</li>\n",
+		"<dt>&lt;key&gt;</dt><dd>&#34;v&#34;</dd>\n",
+		fmt.Sprintf(`<code>%s</code><br><a href="https://example.com/%s?l=%d&amp;x">%[2]s:%[3]d</a>`, st[0].Name(), st[0].path(), st[0].Line()),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToHTML: got %q, want it to contain %q", got, want)
+		}
+	}
+	if n := strings.Count(got, "<summary>Stack trace"); n != 1 {
+		t.Errorf("ToHTML: got %d stack traces, want 1", n)
+	}
+
+	err = Wrap(TruncateStack(Wrap(io.EOF, "read"), 1), "load")
+	got = ToHTML(traced{err, st})
+	if !strings.Contains(got, "<summary>Stack trace 1 of 2</summary>") || strings.Contains(got, "of 3") {
+		t.Errorf("ToHTML: got %q, want 2 stack traces", got)
+	}
+	if strings.Contains(got, "<dl") {
+		t.Errorf("ToHTML: got %q, want no fields", got)
+	}
+}
+
+// traced is an error of another package recording its own stack trace.
+type traced struct {
+	error
+	st StackTrace
+}
+
+func (t traced) StackTrace() StackTrace { return t.st }
+
+func (t traced) Unwrap() error { return t.error }
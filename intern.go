@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// interned holds the interned messages and codes.
+var interned = &internTable{}
+
+type internTable struct {
+	enabled int32 // 1 if size > 0, read without holding mu
+
+	mu      sync.RWMutex
+	size    int
+	strings map[string]string
+
+	hits   uint64
+	misses uint64
+}
+
+// SetInterning enables interning the messages of Wrap, Wrapf, WrapNoStack,
+// WithMessage and WithMessagef, and the codes of WithCode, for up to size
+// distinct strings. Services that annotate errors with the same messages
+// over and over, built with Wrapf for example, then keep a single copy of
+// each message alive instead of one per error. Strings seen once the table
+// is full are not interned. A size of 0, the default, disables interning and
+// empties the table.
+func SetInterning(size int) {
+	size = nonNegative(size)
+	interned.mu.Lock()
+	defer interned.mu.Unlock()
+	interned.size = size
+	if size == 0 {
+		interned.strings = nil
+		atomic.StoreInt32(&interned.enabled, 0)
+		return
+	}
+	if interned.strings == nil {
+		interned.strings = make(map[string]string)
+	}
+	atomic.StoreInt32(&interned.enabled, 1)
+}
+
+// InternStats reports the effectiveness of interning; see SetInterning.
+type InternStats struct {
+	// Hits is the number of strings replaced by an interned copy.
+	Hits uint64
+
+	// Misses is the number of strings that were not interned yet.
+	Misses uint64
+
+	// Size is the number of strings interned.
+	Size int
+}
+
+// HitRate returns the fraction of the strings looked up that were
+// interned already.
+func (s InternStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Interning returns the statistics of interning since the process started.
+func Interning() InternStats {
+	interned.mu.RLock()
+	defer interned.mu.RUnlock()
+	return InternStats{
+		Hits:   atomic.LoadUint64(&interned.hits),
+		Misses: atomic.LoadUint64(&interned.misses),
+		Size:   len(interned.strings),
+	}
+}
+
+// intern returns the interned copy of s, interning s if there is room for
+// it, or s itself if interning is disabled.
+func intern(s string) string {
+	t := interned
+	if atomic.LoadInt32(&t.enabled) == 0 {
+		return s
+	}
+	t.mu.RLock()
+	v, ok := t.strings[s]
+	full := len(t.strings) >= t.size
+	t.mu.RUnlock()
+	if ok {
+		atomic.AddUint64(&t.hits, 1)
+		return v
+	}
+	atomic.AddUint64(&t.misses, 1)
+	if full {
+		// Strings seen once the table is full are not interned: skip the
+		// exclusive lock, which every miss would contend on otherwise.
+		return s
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v, ok := t.strings[s]; ok {
+		return v
+	}
+	if len(t.strings) < t.size {
+		t.strings[s] = s
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSetInterning(t *testing.T) {
+	defer SetInterning(0)
+
+	SetInterning(2)
+	before := Interning()
+	for i := 0; i < 3; i++ {
+		if got, want := Wrapf(io.EOF, "read %s", "file").Error(), "read file: EOF"; got != want {
+			t.Errorf("Wrapf: got %q, want %q", got, want)
+		}
+	}
+	WithCode(io.EOF, "E1")
+	WithMessage(io.EOF, "full")
+	WithMessage(io.EOF, "full")
+	st := Interning()
+	if got, want := st.Hits-before.Hits, uint64(2); got != want {
+		t.Errorf("Hits: got %d, want %d", got, want)
+	}
+	if got, want := st.Misses-before.Misses, uint64(4); got != want {
+		t.Errorf("Misses: got %d, want %d", got, want)
+	}
+	if st.Size != 2 {
+		t.Errorf("Size: got %d, want 2", st.Size)
+	}
+	if s := intern("read file"); s != "read file" {
+		t.Errorf("intern: got %q, want %q", s, "read file")
+	}
+	hits := Interning().Hits
+	WrapNoStack(io.EOF, "read file")
+	if got := Interning().Hits - hits; got != 1 {
+		t.Errorf("WrapNoStack: got %d hits, want 1", got)
+	}
+
+	SetInterning(0)
+	if st := Interning(); st.Size != 0 {
+		t.Errorf("Size once disabled: got %d, want 0", st.Size)
+	}
+	n := Interning().Misses
+	Wrap(io.EOF, "disabled")
+	if Interning().Misses != n {
+		t.Errorf("disabled interning looked a string up")
+	}
+}
+
+func TestInternStatsHitRate(t *testing.T) {
+	tests := []struct {
+		st   InternStats
+		want float64
+	}{
+		{InternStats{}, 0},
+		{InternStats{Hits: 3, Misses: 1}, 0.75},
+	}
+	for i, tt := range tests {
+		if got := tt.st.HitRate(); got != tt.want {
+			t.Errorf("test %d: HitRate(): got %v, want %v", i+1, got, tt.want)
+		}
+	}
+}
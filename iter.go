@@ -0,0 +1,40 @@
+//go:build go1.23
+// +build go1.23
+
+package errors
+
+import "iter"
+
+// All returns an iterator over err and every error in its chain, in the
+// order Walk visits them:
+//
+//	for err := range errors.All(err) {
+//		if c, ok := err.(interface{ Temporary() bool }); ok && c.Temporary() {
+//			return true
+//		}
+//	}
+func All(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		stopped := false
+		Walk(err, func(err error, _ int) bool {
+			if !stopped && !yield(err) {
+				stopped = true
+			}
+			return !stopped
+		})
+	}
+}
+
+// FramesOf returns an iterator over the frames of the stack trace of the
+// first error in err's chain that has one, innermost first. The iterator
+// yields no frames if err has no stack trace.
+func FramesOf(err error) iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		st, _ := stackTraceOf(err)
+		for _, f := range st {
+			if !yield(f) {
+				return
+			}
+		}
+	}
+}
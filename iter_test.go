@@ -0,0 +1,46 @@
+//go:build go1.23
+// +build go1.23
+
+package errors
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	err := WithMessage(&GroupError{Errors: []error{WithMessage(io.EOF, "read"), os.ErrNotExist}}, "load")
+	var got []error
+	for err := range All(err) {
+		got = append(got, err)
+	}
+	if len(got) != 5 || got[0] != err || got[3] != io.EOF || got[4] != os.ErrNotExist {
+		t.Errorf("All: got %v", got)
+	}
+
+	got = nil
+	for err := range All(err) {
+		if err == io.EOF {
+			break
+		}
+		got = append(got, err)
+	}
+	if len(got) != 3 {
+		t.Errorf("All: got %v before breaking", got)
+	}
+}
+
+func TestFramesOf(t *testing.T) {
+	var frames []Frame
+	for f := range FramesOf(Wrap(io.EOF, "read")) {
+		frames = append(frames, f)
+		break
+	}
+	if len(frames) != 1 || frames[0].Name() != "github.com/pkg/errors.TestFramesOf" {
+		t.Errorf("FramesOf: got %v", frames)
+	}
+	for f := range FramesOf(io.EOF) {
+		t.Errorf("FramesOf(io.EOF): got frame %v", f)
+	}
+}
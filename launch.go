@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+	"os"
+)
+
+// Go runs fn in a new goroutine and returns a channel receiving its result
+// once it returns: the error returned by fn, or the panic it raised
+// converted as by Recover. The channel is closed after the result is sent.
+// Errors without a stack trace are annotated with the stack trace of the
+// point Go was called, so that they can be traced back to the code that
+// started the goroutine.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	l := launchSite()
+	go func() {
+		ch <- l.run(fn)
+		close(ch)
+	}()
+	return ch
+}
+
+// Spawn runs fn in a new goroutine like Go, but reports its result, if not
+// nil, with the function set by SetReporter instead of returning it.
+func Spawn(fn func() error) {
+	l := launchSite()
+	go func() {
+		if err := l.run(fn); err != nil {
+			report(err)
+		}
+	}()
+}
+
+// SetReporter sets the function errors nobody waits for, such as those of
+// the goroutines started by Spawn, are reported to. report must be safe
+// to call from any goroutine. A nil report, the default, prints errors
+// with %+v to the standard error.
+func SetReporter(report func(err error)) {
+	updateConfig(func(c *config) { c.reporter = report })
+}
+
+// report reports err with the function set by SetReporter.
+func report(err error) {
+	if r := loadConfig().reporter; r != nil {
+		r(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%+v\n", err)
+}
+
+// launch is the point a goroutine was started by Go or Spawn.
+type launch struct {
+	capture bool
+	stack   stack
+	goid    int64
+}
+
+// launchSite returns the launch of the caller of the function calling
+// launchSite.
+func launchSite() launch {
+	if !captureEnabled() {
+		return launch{}
+	}
+	return launch{true, callers(1), currentGoroutine()}
+}
+
+// run returns the result of fn, annotated with the stack trace of l if it
+// has none.
+func (l launch) run(fn func() error) (err error) {
+	defer Recover(&err)
+	err = fn()
+	if err != nil && l.capture && !hasStack(err) {
+		err = &withStack{err, l.stack, l.goid}
+	}
+	return err
+}
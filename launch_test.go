@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestGo(t *testing.T) {
+	ch := Go(func() error { return io.EOF })
+	err := <-ch
+	if !Is(err, io.EOF) {
+		t.Fatalf("Go: got %v, want io.EOF", err)
+	}
+	if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Name() != "github.com/pkg/errors.TestGo" {
+		t.Errorf("Go: got stack trace %v, want the launch site", st)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("Go: channel not closed after the result")
+	}
+
+	stacked := New("stacked")
+	if err := <-Go(func() error { return stacked }); err != stacked {
+		t.Errorf("Go: got %v, want %v as is", err, stacked)
+	}
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Errorf("Go: got %v, want nil", err)
+	}
+	if err := <-Go(func() error { panic("boom") }); !IsPanic(err) {
+		t.Errorf("Go: got %v, want a panic", err)
+	}
+}
+
+func TestSpawn(t *testing.T) {
+	defer SetReporter(nil)
+
+	reported := make(chan error, 1)
+	SetReporter(func(err error) { reported <- err })
+	Spawn(func() error { return nil })
+	Spawn(func() error { panic("boom") })
+	if err := <-reported; !IsPanic(err) {
+		t.Errorf("Spawn: reported %v, want a panic", err)
+	}
+}
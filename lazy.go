@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// WrapLazy returns an error annotating err with a stack trace at the point
+// WrapLazy is called, and the message returned by message. message is
+// called at most once, the first time the message of the error is needed,
+// which saves building messages of errors that are discarded, by retry
+// loops for example, without ever being printed.
+// If err is nil, WrapLazy returns nil.
+func WrapLazy(err error, message func() string) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, "", &lazyMessage{fn: message})
+}
+
+// SetDeferredFormatting makes Errorf and Wrapf defer formatting their
+// message until it is first needed, like WrapLazy. The arguments are then
+// formatted after the function returns, so they must not be modified
+// afterwards; values that are not safe to format concurrently must not be
+// passed either. Formats using the %w verb are always formatted right away.
+// Deferred formatting is disabled by default.
+func SetDeferredFormatting(enabled bool) {
+	updateConfig(func(c *config) { c.deferFormatting = enabled })
+}
+
+// lazyMessage is a message computed when first needed, either by calling
+// fn or by formatting args according to format.
+type lazyMessage struct {
+	once   sync.Once
+	fn     func() string
+	format string
+	args   []interface{}
+	msg    string
+}
+
+func (l *lazyMessage) String() string {
+	l.once.Do(func() {
+		if l.fn != nil {
+			l.msg = l.fn()
+		} else {
+			l.msg = fmt.Sprintf(l.format, l.args...)
+		}
+		l.fn, l.args = nil, nil
+	})
+	return l.msg
+}
+
+// lazyError is an error whose message is computed when first needed.
+type lazyError struct {
+	lazyMessage
+}
+
+func (e *lazyError) Error() string { return e.String() }
+
+func (*lazyError) own() {}
+
+// errorf returns fmt.Errorf(format, args...), formatted lazily if deferred
+// formatting is enabled and format does not wrap an error.
+func errorf(format string, args []interface{}) error {
+	if !loadConfig().deferFormatting || wrapsError(format) {
+		return fmt.Errorf(format, args...)
+	}
+	return &lazyError{lazyMessage{format: format, args: args}}
+}
+
+// wrapsError reports whether format has a %w verb, including with flags or
+// an explicit argument index, such as "%[2]w".
+func wrapsError(format string) bool {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Skip the flags, width, precision and argument indexes.
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0 {
+			i++
+		}
+		if i < len(format) && format[i] == 'w' {
+			return true
+		}
+	}
+	return false
+}
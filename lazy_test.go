@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestWrapLazy(t *testing.T) {
+	if got := WrapLazy(nil, func() string { return "unused" }); got != nil {
+		t.Errorf("WrapLazy(nil): got %#v, want nil", got)
+	}
+
+	calls := 0
+	err := WrapLazy(io.EOF, func() string {
+		calls++
+		return "lazy"
+	})
+	if calls != 0 {
+		t.Fatalf("WrapLazy: message built %d times before use, want 0", calls)
+	}
+	for i := 0; i < 2; i++ {
+		if got := err.Error(); got != "lazy: EOF" {
+			t.Errorf("Error(): got %q, want %q", got, "lazy: EOF")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("WrapLazy: message built %d times, want 1", calls)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(err, io.EOF): got false, want true")
+	}
+	testFormatRegexp(t, 0, err, "%+v", "lazy: EOF\ngithub.com/pkg/errors.TestWrapLazy\n\t.+/lazy_test.go:15")
+}
+
+type countingStringer struct{ n *int }
+
+func (c countingStringer) String() string {
+	*c.n++
+	return "arg"
+}
+
+func TestSetDeferredFormatting(t *testing.T) {
+	defer SetDeferredFormatting(false)
+
+	n := 0
+	arg := countingStringer{&n}
+	SetDeferredFormatting(true)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{Errorf("errorf %v", arg), "errorf arg"},
+		{Wrapf(io.EOF, "wrapf %v", arg), "wrapf arg: EOF"},
+	}
+	if n != 0 {
+		t.Fatalf("deferred formatting: arguments formatted %d times before use, want 0", n)
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if got := fmt.Sprintf("%v", tt.err); got != tt.want {
+			t.Errorf("test %d: %%v: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+	if n != len(tests) {
+		t.Errorf("deferred formatting: arguments formatted %d times, want %d", n, len(tests))
+	}
+
+	for _, tt := range []struct {
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"errorf: %w", []interface{}{io.EOF}, "errorf: EOF"},
+		{"errorf: %[1]w", []interface{}{io.EOF}, "errorf: EOF"},
+		{"%d: %[2]w", []interface{}{1, io.EOF}, "1: EOF"},
+		{"100%%: %+w", []interface{}{io.EOF}, "100%: EOF"},
+	} {
+		wrapped := Errorf(tt.format, tt.args...)
+		if !Is(wrapped, io.EOF) {
+			t.Errorf("Errorf(%q): Is(err, io.EOF): got false, want true", tt.format)
+		}
+		if got := wrapped.Error(); got != tt.want {
+			t.Errorf("Errorf(%q): got %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if wrapsError("100%%w") {
+		t.Errorf("wrapsError(%q): got true, want false", "100%%w")
+	}
+
+	SetDeferredFormatting(false)
+	n = 0
+	_ = Errorf("errorf %v", arg)
+	if n != 1 {
+		t.Errorf("eager formatting: arguments formatted %d times, want 1", n)
+	}
+}
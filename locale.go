@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Catalog translates the messages of the errors created from templates;
+// see Localize. Message returns the message of key in the language lang,
+// a BCP 47 tag such as "fr-CA", formatted with args, and whether the
+// catalog has a translation of key. A catalog built with
+// golang.org/x/text/message can be adapted as follows:
+//
+//	type catalog struct{ c catalog.Catalog }
+//
+//	func (c catalog) Message(lang, key string, args ...interface{}) (string, bool) {
+//		tag := language.Make(lang)
+//		if _, _, confidence := c.c.Matcher().Match(tag); confidence == language.No {
+//			return "", false
+//		}
+//		return message.NewPrinter(tag, message.Catalog(c.c)).Sprintf(key, args...), true
+//	}
+type Catalog interface {
+	Message(lang, key string, args ...interface{}) (string, bool)
+}
+
+// SetCatalog sets the Catalog Localize translates messages with. A nil
+// catalog, the default, leaves messages untranslated.
+func SetCatalog(c Catalog) {
+	updateConfig(func(cfg *config) { cfg.catalog = c })
+}
+
+// Template creates errors whose message can be translated for end users
+// by Localize, while Error returns it in the language it was written in,
+// for logs and other internal uses:
+//
+//	var ErrQuota = errors.NewTemplate("quota.exceeded", "quota of %d requests exceeded")
+//
+//	return ErrQuota.New(limit)
+type Template struct {
+	key    string
+	format string
+}
+
+// NewTemplate returns the Template of the messages identified by key in
+// catalogs, formatted with format, according to the fmt package, when not
+// translated.
+func NewTemplate(key, format string) *Template {
+	return &Template{key, format}
+}
+
+// Key returns the key identifying the messages of t.
+func (t *Template) Key() string { return t.key }
+
+// New returns an error whose message is the format of t formatted with
+// args, recording the stack trace at the point New was called, like New.
+func (t *Template) New(args ...interface{}) error {
+	l := t.localized(nil, args)
+	if !captureEnabled() {
+		return l
+	}
+	return &withStack{l, callers(0), currentGoroutine()}
+}
+
+// Wrap returns an error annotating err with the format of t formatted with
+// args, and the stack trace at the point Wrap was called unless err has
+// one, like Wrap. If err is nil, Wrap returns nil.
+func (t *Template) Wrap(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	l := t.localized(err, args)
+	if !captureEnabled() || hasStack(err) {
+		return l
+	}
+	return &withStack{l, callers(0), currentGoroutine()}
+}
+
+func (t *Template) localized(cause error, args []interface{}) *localized {
+	return &localized{cause, t.key, args, fmt.Sprintf(t.format, args...)}
+}
+
+// Localize returns the message of err translated into the language lang,
+// a BCP 47 tag such as "fr-CA", by the Catalog set with SetCatalog: the
+// messages of the layers of err's chain created from a Template the catalog
+// has a translation of are translated, the others are kept as they are.
+// Localize returns the empty string if err is nil.
+func Localize(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	c := loadConfig().catalog
+	ls := layers(err)
+	msgs := Messages(err)
+	for i, l := range ls {
+		for isAnnotation(l) {
+			l = Unwrap(l)
+		}
+		e, ok := l.(*localized)
+		if !ok || c == nil {
+			continue
+		}
+		if msg, ok := c.Message(lang, e.key, e.args...); ok {
+			msgs[i] = msg
+		}
+	}
+	return strings.Join(msgs, ": ")
+}
+
+// localized is an error created from a Template.
+type localized struct {
+	cause error // nil unless created by Template.Wrap
+	key   string
+	args  []interface{}
+	msg   string // the untranslated message
+}
+
+func (e *localized) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *localized) Cause() error { return e.cause }
+
+func (e *localized) Unwrap() error { return e.cause }
+
+func (e *localized) Format(s fmt.State, verb rune) { formatError(s, verb, e) }
+
+func (*localized) own() {}
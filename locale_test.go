@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// catalog is a Catalog keyed by language and message key.
+type catalog map[string]map[string]string
+
+func (c catalog) Message(lang, key string, args ...interface{}) (string, bool) {
+	format, ok := c[lang][key]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+func TestLocalize(t *testing.T) {
+	defer SetCatalog(nil)
+
+	quota := NewTemplate("quota.exceeded", "quota of %d requests exceeded")
+	read := NewTemplate("read", "cannot read %s")
+	err := Wrap(read.Wrap(quota.New(10), "config"), "load")
+	if got, want := err.Error(), "load: cannot read config: quota of 10 requests exceeded"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !hasStack(err) || quota.Key() != "quota.exceeded" {
+		t.Errorf("got no stack trace or key %q", quota.Key())
+	}
+	if got, want := Localize(err, "fr"), err.Error(); got != want {
+		t.Errorf("Localize without catalog: got %q, want %q", got, want)
+	}
+
+	SetCatalog(catalog{"fr": {
+		"quota.exceeded": "quota de %d requêtes dépassé",
+		"read":           "impossible de lire %s",
+	}})
+	tests := []struct {
+		err  error
+		lang string
+		want string
+	}{
+		{nil, "fr", ""},
+		{err, "fr", "load: impossible de lire config: quota de 10 requêtes dépassé"},
+		{err, "de", err.Error()},
+		{WithCode(quota.Wrap(io.EOF, 3), "E1"), "fr", "quota de 3 requêtes dépassé: EOF"},
+	}
+	for i, tt := range tests {
+		if got := Localize(tt.err, tt.lang); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	c := Transform(err, func(err error) error { return err })
+	if got, want := Localize(c, "fr"), Localize(err, "fr"); got != want {
+		t.Errorf("Localize(Transform(err)): got %q, want %q", got, want)
+	}
+	if quota.Wrap(nil) != nil {
+		t.Errorf("Wrap(nil): got non-nil error")
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ToMarkdown returns a rendering of err suitable for pasting into issue
+// trackers and chat tools that support Markdown: the message of err as a
+// bold summary line, followed by a collapsible section holding the %+v
+// output of err in a fenced code block. When URL templates are set with
+// SetPathOptions, the section also lists the frames of the stack traces of
+// err, each linked to its source; see Frame.URL. ToMarkdown returns the
+// empty string if err is nil.
+func ToMarkdown(err error) string {
+	if err == nil {
+		return ""
+	}
+	details := fmt.Sprintf("%+v", err)
+	fence := "```"
+	for strings.Contains(details, fence) {
+		fence += "`"
+	}
+
+	var b strings.Builder
+	b.WriteString("**")
+	b.WriteString(markdownEscaper.Replace(firstLine(err.Error())))
+	b.WriteString("**\n\n<details>\n<summary>Details</summary>\n\n")
+	b.WriteString(fence)
+	b.WriteString("\n")
+	b.WriteString(details)
+	b.WriteString("\n")
+	b.WriteString(fence)
+	b.WriteString("\n")
+	writeMarkdownStacks(&b, err)
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+// writeMarkdownStacks writes the stack traces of err as numbered lists of
+// frames linked to their source, if any frame has a URL.
+func writeMarkdownStacks(b *strings.Builder, err error) {
+	stacks := stackTraces(err)
+	linked := false
+	for _, st := range stacks {
+		for _, f := range st.visible() {
+			linked = linked || f.URL() != ""
+		}
+	}
+	if !linked {
+		return
+	}
+	for i, st := range stacks {
+		b.WriteString("\nStack trace")
+		if len(stacks) > 1 {
+			b.WriteString(" " + strconv.Itoa(i+1) + " of " + strconv.Itoa(len(stacks)))
+		}
+		b.WriteString(":\n\n")
+		for j, f := range st.visible() {
+			loc := markdownEscaper.Replace(f.path() + ":" + strconv.Itoa(f.Line()))
+			b.WriteString(strconv.Itoa(j+1) + ". `" + f.Name() + "` ")
+			if url := f.URL(); url != "" {
+				b.WriteString("[" + loc + "](" + markdownURLEscaper.Replace(url) + ")")
+			} else {
+				b.WriteString(loc)
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+// markdownURLEscaper escapes the characters ending the destination of a
+// link.
+var markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
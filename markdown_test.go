@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	if got := ToMarkdown(nil); got != "" {
+		t.Errorf("ToMarkdown(nil): got %q, want empty", got)
+	}
+
+	err := Wrap(io.EOF, "read *config*")
+	got := ToMarkdown(err)
+	want := "**read \\*config\\*: EOF**\n\n<details>\n<summary>Details</summary>\n\n```\n" +
+		fmt.Sprintf("%+v", err) +
+		"\n```\n\n</details>\n"
+	if got != want {
+		t.Errorf("ToMarkdown:\n got %q\nwant %q", got, want)
+	}
+
+	got = ToMarkdown(New("first line\n```\nsecond line"))
+	if !strings.HasPrefix(got, "**first line**\n") {
+		t.Errorf("ToMarkdown: got %q, want only the first line in the summary", got)
+	}
+	if !strings.Contains(got, "\n````\nfirst line\n```\nsecond line") {
+		t.Errorf("ToMarkdown: got %q, want a fence longer than the fences in the details", got)
+	}
+}
+
+func TestToMarkdownLinks(t *testing.T) {
+	defer SetPathOptions(PathOptions{})
+
+	SetPathOptions(PathOptions{
+		ModuleURLs: map[string]string{
+			"github.com/pkg/errors": "https://example.com/errors/{file}#L{line}",
+		},
+	})
+	got := ToMarkdown(New("linked"))
+	for _, want := range []string{
+		"\nStack trace:\n\n1. `github.com/pkg/errors.TestToMarkdownLinks` [",
+		"/markdown\\_test.go:41](https://example.com/errors/markdown_test.go#L41)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ToMarkdown: got %q, want the frames linked to their source", got)
+		}
+	}
+	if !strings.HasSuffix(got, "\n\n</details>\n") {
+		t.Errorf("ToMarkdown: got %q, want the details closed", got)
+	}
+}
@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// IsAny reports whether err matches any of targets, as defined by Is. It
+// collapses the chains of calls to Is handlers are often made of:
+//
+//	if errors.IsAny(err, fs.ErrNotExist, fs.ErrPermission) {
+//		http.Error(w, "not found", http.StatusNotFound)
+//	}
+//
+// IsAny reports false if targets is empty.
+func IsAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAll reports whether err matches all of targets, as defined by Is, which
+// is possible when err's chain, or the errors it joins, match several
+// targets. IsAll reports true if targets is empty.
+func IsAll(err error, targets ...error) bool {
+	for _, target := range targets {
+		if !Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the first error in err's chain for which pred reports true,
+// and reports whether there is one. Unlike Is and As, pred can match any
+// condition, such as the message of an error matching a regular expression
+// or the presence of a field. The chain is searched depth-first, including
+// every error joined by the errors with an Unwrap() []error method, such
+// as the errors returned by Group.Wait.
+func Find(err error, pred func(error) bool) (error, bool) {
+	var found error
+	ok := find(err, func(err error) bool {
+		if pred(err) {
+			found = err
+			return true
+		}
+		return false
+	})
+	return found, ok
+}
+
+// Has reports whether pred reports true for any error in err's chain,
+// searched as by Find.
+func Has(err error, pred func(error) bool) bool {
+	return find(err, pred)
+}
+
+// Ignore returns nil if err matches any of targets, as defined by Is, and
+// err otherwise. It discards the errors that are expected, such as io.EOF
+// at the end of a stream:
+//
+//	return errors.Ignore(os.Remove(path), fs.ErrNotExist)
+func Ignore(err error, targets ...error) error {
+	if IsAny(err, targets...) {
+		return nil
+	}
+	return err
+}
+
+// MatchMessage reports whether the message of any layer of err's chain, as
+// returned by Messages, without the messages of the layers it wraps,
+// matches pattern. It identifies the errors of packages that do not export
+// them, matching them in one place rather than with ad hoc string
+// comparisons:
+//
+//	if errors.MatchMessage(err, "*connection reset*") {
+//		retry()
+//	}
+//
+// A pattern starting with "re:" is a regular expression, with the syntax
+// of package regexp, matched against any part of the messages. Other
+// patterns are globs matched against whole messages, in which '*' matches
+// any sequence of characters, '?' matches any single character and '\'
+// escapes the character following it. MatchMessage reports false if
+// pattern is not a valid regular expression.
+func MatchMessage(err error, pattern string) bool {
+	re := messagePattern(pattern)
+	if re == nil {
+		return false
+	}
+	for _, msg := range Messages(err) {
+		if re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// messagePatterns caches the regular expressions of the patterns of
+// MatchMessage, nil for invalid ones.
+var messagePatterns sync.Map // map[string]*regexp.Regexp
+
+// messagePattern returns the regular expression of pattern, or nil if it
+// is not valid.
+func messagePattern(pattern string) *regexp.Regexp {
+	if re, ok := messagePatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	expr := strings.TrimPrefix(pattern, "re:")
+	if expr == pattern {
+		expr = globExpr(pattern)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = nil
+	}
+	messagePatterns.Store(pattern, re)
+	return re
+}
+
+// globExpr returns the regular expression matching the messages glob
+// matches.
+func globExpr(glob string) string {
+	var b strings.Builder
+	b.WriteString("^(?s:")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		case r == '\\' && i+1 < len(runes):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(")$")
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestIsAny(t *testing.T) {
+	err := Wrap(Wrap(io.EOF, "read"), "load")
+	group := &GroupError{Errors: []error{io.EOF, os.ErrNotExist}}
+	tests := []struct {
+		err     error
+		targets []error
+		any     bool
+		all     bool
+	}{
+		{err, nil, false, true},
+		{err, []error{io.EOF}, true, true},
+		{err, []error{os.ErrNotExist, io.EOF}, true, false},
+		{err, []error{os.ErrNotExist, io.ErrUnexpectedEOF}, false, false},
+		{group, []error{io.EOF, os.ErrNotExist}, true, true},
+		{nil, []error{io.EOF}, false, false},
+	}
+	for i, tt := range tests {
+		if got := IsAny(tt.err, tt.targets...); got != tt.any {
+			t.Errorf("test %d: IsAny(%v, %v): got %t, want %t", i+1, tt.err, tt.targets, got, tt.any)
+		}
+		if got := IsAll(tt.err, tt.targets...); got != tt.all {
+			t.Errorf("test %d: IsAll(%v, %v): got %t, want %t", i+1, tt.err, tt.targets, got, tt.all)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	inner := WithFields(io.EOF, Label("offset", 42))
+	err := Wrap(&GroupError{Errors: []error{os.ErrNotExist, Wrap(inner, "read")}}, "load")
+	hasOffset := func(err error) bool {
+		w, ok := err.(*withFields)
+		return ok && w.fields[0].Key == "offset"
+	}
+	if got, ok := Find(err, hasOffset); !ok || got != inner {
+		t.Errorf("Find: got %v, %t, want %v", got, ok, inner)
+	}
+	if !Has(err, hasOffset) {
+		t.Errorf("Has: got false")
+	}
+	isNil := func(err error) bool { return err == nil }
+	if got, ok := Find(err, isNil); ok || got != nil {
+		t.Errorf("Find: got %v, %t for a predicate matching nothing", got, ok)
+	}
+	if Has(nil, func(error) bool { return true }) {
+		t.Errorf("Has(nil): got true")
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	if got := Ignore(err, os.ErrNotExist, io.EOF); got != nil {
+		t.Errorf("Ignore: got %v, want nil", got)
+	}
+	if got := Ignore(err, os.ErrNotExist); got != err {
+		t.Errorf("Ignore: got %v, want %v", got, err)
+	}
+	if got := Ignore(err); got != err {
+		t.Errorf("Ignore: got %v without targets, want %v", got, err)
+	}
+	if got := Ignore(nil, io.EOF); got != nil {
+		t.Errorf("Ignore(nil): got %v", got)
+	}
+}
+
+func TestMatchMessage(t *testing.T) {
+	err := Wrap(fmt.Errorf("dial tcp: %w", New("connection reset by peer")), "fetch für")
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"connection reset by peer", true},
+		{"connection*", true},
+		{"*reset*", true},
+		{"dial ???", true},
+		{"dial", false},
+		{"fetch f?r", true},
+		{"*: connection reset by peer", false},
+		{`dial tcp\*`, false},
+		{"re:reset by", true},
+		{"re:^dial tcp$", true},
+		{"re:^dial tcp: connection", false},
+		{"re:(", false},
+	}
+	for i, tt := range tests {
+		if got := MatchMessage(err, tt.pattern); got != tt.want {
+			t.Errorf("test %d: MatchMessage(%q): got %t, want %t", i+1, tt.pattern, got, tt.want)
+		}
+	}
+	if MatchMessage(nil, "*") {
+		t.Errorf("MatchMessage(nil): got true")
+	}
+	if !MatchMessage(New("a*b"), `a\*b`) || MatchMessage(New("axb"), `a\*b`) {
+		t.Errorf("MatchMessage: escaped '*' not matched literally")
+	}
+}
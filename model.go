@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ErrorModel is the structure of an error, as returned by Model: one
+// ErrorModel per layer of its chain, each holding the error it wraps, or
+// the errors, for errors wrapping several ones such as GroupError.
+type ErrorModel struct {
+	// Type is the type of the error of the layer, such as
+	// "*errors.withMessage" or "*fs.PathError".
+	Type string
+
+	// Message is the message of the layer alone, without the messages
+	// of the errors it wraps.
+	Message string
+
+	// Code and Kind are those the layer was annotated with, if any.
+	Code string
+	Kind Kind
+
+	// Fields and Hints are those the layer was annotated with.
+	Fields []Field
+	Hints  []string
+
+	// Stack holds the frames of the stack trace recorded with the
+	// layer, innermost first, if any.
+	Stack []FrameModel
+
+	// Cause is the next layer of the chain, nil for the last one.
+	Cause *ErrorModel
+
+	// Errors are the errors the layer wraps, if it wraps several.
+	Errors []*ErrorModel
+}
+
+// FrameModel is a frame of a stack trace as described by Model.
+type FrameModel struct {
+	Function    string
+	File        string // rendered as set with SetPathOptions
+	Line        int
+	URL         string // see Frame.URL
+	Application bool   // see Frame.IsApplication
+}
+
+// Model returns the structure of err as plain values, so that programs can
+// define their own renderings of errors, with RenderTemplate for example,
+// without parsing the output of %+v. Model returns nil if err is nil.
+func Model(err error) *ErrorModel {
+	return modelWithin(err, maxChainDepth)
+}
+
+func modelWithin(err error, n int) *ErrorModel {
+	if err == nil || n == 0 {
+		return nil
+	}
+	m := &ErrorModel{}
+	var affixes []*withAffixes
+	for ; isAnnotation(err) && n > 0; n, err = n-1, Unwrap(err) {
+		switch w := err.(type) {
+		case *withCode:
+			if m.Code == "" {
+				m.Code = w.code
+			}
+		case *withKind:
+			if m.Kind == "" {
+				m.Kind = w.kind
+			}
+		case *withFields:
+			m.Fields = append(m.Fields, w.fields...)
+		case *withDetached:
+			m.Fields = append(m.Fields, w.fields...)
+		case *withHint:
+			m.Hints = append(m.Hints, w.hint)
+		case *withAffixes:
+			affixes = append(affixes, w)
+		}
+		if s, ok := err.(interface{ StackTrace() StackTrace }); ok && m.Stack == nil {
+			m.Stack = frameModels(s.StackTrace())
+		}
+	}
+	if err == nil {
+		return m
+	}
+	m.Type = fmt.Sprintf("%T", err)
+	var next error
+	switch e := err.(type) {
+	case *withMessage:
+		m.Message = e.message()
+		next = e.cause
+		if ws, ok := e.wrapStack(); ok {
+			if m.Stack == nil {
+				m.Stack = frameModels(ws.StackTrace())
+			}
+			next = ws.error
+		}
+	case interface{ Unwrap() []error }:
+		m.Message = err.Error()
+		for _, err := range e.Unwrap() {
+			m.Errors = append(m.Errors, modelWithin(err, n-1))
+		}
+	default:
+		next = Unwrap(err)
+		if next != nil {
+			m.Message = layerMessage(err, next.Error())
+		} else {
+			m.Message = err.Error()
+		}
+		if s, ok := err.(interface{ StackTrace() StackTrace }); ok && m.Stack == nil {
+			m.Stack = frameModels(s.StackTrace())
+		}
+	}
+	for i := len(affixes) - 1; i >= 0; i-- {
+		m.Message = affixes[i].prefix + m.Message + affixes[i].suffix
+	}
+	m.Cause = modelWithin(next, n-1)
+	return m
+}
+
+// frameModels returns the models of the visible frames of st.
+func frameModels(st StackTrace) []FrameModel {
+	st = st.visible()
+	if len(st) == 0 {
+		return nil
+	}
+	fs := make([]FrameModel, len(st))
+	for i, f := range st {
+		fs[i] = FrameModel{
+			Function:    f.Name(),
+			File:        f.path(),
+			Line:        f.Line(),
+			URL:         f.URL(),
+			Application: f.IsApplication(),
+		}
+	}
+	return fs
+}
+
+// RenderTemplate renders err with t, executed with the model of err
+// returned by Model as data:
+//
+//	t := template.Must(template.New("error").Parse(
+//		`{{.Message}}{{with .Code}} [{{.}}]{{end}}{{range .Stack}}
+//		at {{.Function}}{{end}}`))
+//	s, err := errors.RenderTemplate(t, err)
+//
+// RenderTemplate returns the error returned by t.Execute, if any.
+func RenderTemplate(t *template.Template, err error) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, Model(err)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestModel(t *testing.T) {
+	if Model(nil) != nil {
+		t.Errorf("Model(nil): got non-nil model")
+	}
+
+	inner := New("inner")
+	st, _ := stackTraceOf(inner)
+	err := WithCode(Wrap(WithFields(WithHint(inner, "retry"), Label("a", 1)), "outer"), "E1")
+	m := Model(err)
+	if m.Type != "*errors.withMessage" || m.Message != "outer" || m.Code != "E1" || m.Stack != nil || m.Cause == nil {
+		t.Fatalf("Model: got %+v, want the outer layer", m)
+	}
+	c := m.Cause
+	if c.Type != "*errors.errorString" || c.Message != "inner" || len(c.Fields) != 1 || c.Fields[0].Key != "a" ||
+		len(c.Hints) != 1 || c.Hints[0] != "retry" || c.Cause != nil {
+		t.Fatalf("Model: got cause %+v, want the inner layer", c)
+	}
+	if len(c.Stack) != len(st) || c.Stack[0].Function != st[0].Name() || c.Stack[0].Line != st[0].Line() {
+		t.Errorf("Model: got stack %+v, want %v", c.Stack, st)
+	}
+
+	m = Model(Prefix(Wrap(io.EOF, "read"), "op"))
+	if m.Message != "op: read" || len(m.Stack) == 0 || m.Cause == nil || m.Cause.Message != "EOF" || m.Cause.Stack != nil {
+		t.Errorf("Model(Wrap): got %+v and cause %+v, want the stack trace in the outer layer", m, m.Cause)
+	}
+
+	m = Model(WithMessage(&GroupError{Errors: []error{io.EOF, io.ErrUnexpectedEOF}}, "all"))
+	if g := m.Cause; g == nil || len(g.Errors) != 2 || g.Errors[1].Message != "unexpected EOF" || g.Cause != nil {
+		t.Errorf("Model(GroupError): got %+v, want the two errors", m.Cause)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("error").Parse(
+		`{{.Message}}{{with .Code}} [{{.}}]{{end}}{{with .Cause}} <- {{.Message}}{{end}}`))
+	got, err := RenderTemplate(tmpl, WithCode(WithMessage(io.EOF, "read"), "E1"))
+	if err != nil || got != "read [E1] <- EOF" {
+		t.Errorf("RenderTemplate: got %q, %v, want %q", got, err, "read [E1] <- EOF")
+	}
+
+	tmpl = template.Must(template.New("error").Parse(`{{.Missing}}`))
+	if _, err := RenderTemplate(tmpl, io.EOF); err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("RenderTemplate: got error %v, want the error of the template", err)
+	}
+}
@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+var (
+	buildModuleOnce sync.Once
+	buildModule     string
+)
+
+// mainModule returns the import path of the main module, as set with
+// SetMainModule or, failing that, as recorded in the binary's build
+// information.
+func mainModule() string {
+	if m := loadConfig().mainModule; m != "" {
+		return m
+	}
+	buildModuleOnce.Do(func() {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			buildModule = bi.Main.Path
+		}
+	})
+	return buildModule
+}
+
+// SetMainModule overrides the module path used to tell application frames
+// from dependency frames. By default the path of the main module is read
+// from the binary's build information, which is not available for binaries
+// built outside of module mode. An empty path restores the default.
+func SetMainModule(path string) {
+	updateConfig(func(c *config) { c.mainModule = strings.TrimSuffix(path, "/") })
+}
+
+// IsApplication reports whether the function of this frame belongs to the
+// main module of the program, or to package main, rather than to one of its
+// dependencies or the standard library.
+func (f Frame) IsApplication() bool {
+	p := pkgname(f.Name())
+	if p == "main" {
+		return true
+	}
+	m := mainModule()
+	return m != "" && (p == m || strings.HasPrefix(p, m+"/"))
+}
+
+// SetApplicationFrameMarker sets a marker, such as "> ", that is printed in
+// front of application frames when a stack trace is formatted with %+v, so
+// they stand out from the frames of dependencies. An empty marker, the
+// default, disables marking.
+func SetApplicationFrameMarker(marker string) {
+	updateConfig(func(c *config) { c.appMarker = marker })
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFrameIsApplication(t *testing.T) {
+	defer SetMainModule("")
+
+	SetMainModule("github.com/pkg")
+	if !initpc.IsApplication() {
+		t.Errorf("%+v: IsApplication() = false, want true", initpc)
+	}
+	if Frame(0).IsApplication() {
+		t.Errorf("unknown frame: IsApplication() = true, want false")
+	}
+
+	SetMainModule("github.com/pkg/err")
+	if initpc.IsApplication() {
+		t.Errorf("%+v: IsApplication() with module %q = true, want false", initpc, "github.com/pkg/err")
+	}
+}
+
+func TestSetApplicationFrameMarker(t *testing.T) {
+	defer SetMainModule("")
+	defer SetApplicationFrameMarker("")
+
+	SetMainModule("github.com/pkg/errors")
+	SetApplicationFrameMarker("> ")
+	got := fmt.Sprintf("%+v", New("marked"))
+	if !strings.Contains(got, "\n> github.com/pkg/errors.TestSetApplicationFrameMarker\n") {
+		t.Errorf("%%+v: got %q, want the application frame marked", got)
+	}
+	if !strings.Contains(got, "\ntesting.tRunner\n") {
+		t.Errorf("%%+v: got %q, want dependency frames unmarked", got)
+	}
+}
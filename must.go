@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+// Must returns v if err is nil, and panics with err otherwise. The panic
+// raised prints err with %+v, including the stack trace of the point Must
+// was called if err has none, if it crashes the program; Recover converts
+// it back to err. Must is meant for initialisation, where an error cannot
+// be handled:
+//
+//	var tmpl = errors.Must(template.ParseFiles("index.html"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		mustFail(err)
+	}
+	return v
+}
+
+// Must2 is like Must for functions returning two values and an error.
+func Must2[T, U any](v T, w U, err error) (T, U) {
+	if err != nil {
+		mustFail(err)
+	}
+	return v, w
+}
+
+// Must3 is like Must for functions returning three values and an error.
+func Must3[T, U, V any](v T, w U, x V, err error) (T, U, V) {
+	if err != nil {
+		mustFail(err)
+	}
+	return v, w, x
+}
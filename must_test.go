@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMust(t *testing.T) {
+	if got := Must(42, nil); got != 42 {
+		t.Errorf("Must: got %v, want 42", got)
+	}
+	if a, b := Must2("a", 2, nil); a != "a" || b != 2 {
+		t.Errorf("Must2: got %v, %v", a, b)
+	}
+	if a, b, c := Must3("a", 2, 3.0, nil); a != "a" || b != 2 || c != 3.0 {
+		t.Errorf("Must3: got %v, %v, %v", a, b, c)
+	}
+
+	var v interface{}
+	err := func() (err error) {
+		defer func() {
+			v = recover()
+			err = FromPanic(v)
+		}()
+		Must(0, io.EOF)
+		return nil
+	}()
+	if !Is(err, io.EOF) {
+		t.Fatalf("recovered: got %v, want io.EOF", err)
+	}
+	if IsPanic(err) {
+		t.Errorf("recovered: got a panic, want the error passed to Must")
+	}
+	if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Line() != 29 {
+		t.Errorf("recovered: got stack trace %v, want one starting at must_test.go:29", st)
+	}
+	perr, ok := v.(error)
+	if !ok || !strings.HasPrefix(perr.Error(), "EOF\ngithub.com/pkg/errors.TestMust") {
+		t.Errorf("panic value: got %v, want the error with its stack trace", v)
+	}
+	if !Is(perr, io.EOF) {
+		t.Errorf("Is(panic value, io.EOF): got false, want true")
+	}
+
+	stacked := New("stacked")
+	err = func() (err error) {
+		defer Recover(&err)
+		Must2(0, 0, stacked)
+		return nil
+	}()
+	if err != stacked {
+		t.Errorf("recovered: got %v, want %v as is", err, stacked)
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "sync"
+
+// Once holds the first non-nil error set on it, for concurrent code where
+// the first failure wins, such as workers racing to complete a task. The
+// zero Once holds no error; a Once must not be copied after first use.
+type Once struct {
+	mu        sync.Mutex
+	first     error
+	discarded int
+}
+
+// Set records err if it is the first non-nil error set on o, and reports
+// whether it did. Later errors are discarded and counted; nil errors are
+// ignored.
+func (o *Once) Set(err error) bool {
+	if err == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.first == nil {
+		o.first = err
+		return true
+	}
+	o.discarded++
+	return false
+}
+
+// Err returns the first error set on o, or nil.
+func (o *Once) Err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.first
+}
+
+// Discarded returns the number of non-nil errors set on o after the first.
+func (o *Once) Discarded() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.discarded
+}
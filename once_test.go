@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestOnce(t *testing.T) {
+	var o Once
+	if o.Err() != nil || o.Discarded() != 0 {
+		t.Errorf("zero Once: got %v, %d discarded", o.Err(), o.Discarded())
+	}
+	if o.Set(nil) {
+		t.Errorf("Set(nil): got true, want false")
+	}
+	if !o.Set(io.EOF) {
+		t.Errorf("Set(io.EOF): got false, want true")
+	}
+	if o.Set(io.ErrUnexpectedEOF) {
+		t.Errorf("second Set: got true, want false")
+	}
+	if o.Err() != io.EOF || o.Discarded() != 1 {
+		t.Errorf("Once: got %v, %d discarded, want io.EOF, 1 discarded", o.Err(), o.Discarded())
+	}
+}
+
+func TestOnceConcurrently(t *testing.T) {
+	var o Once
+	var wg sync.WaitGroup
+	won := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := New("worker")
+			if o.Set(err) {
+				won <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(won)
+	first := <-won
+	if _, more := <-won; more {
+		t.Errorf("Set: more than one error won")
+	}
+	if o.Err() != first || o.Discarded() != 9 {
+		t.Errorf("Once: got %v, %d discarded, want %v, 9 discarded", o.Err(), o.Discarded(), first)
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Opaque returns an error with the same message and stack trace as err,
+// printed alike by %+v, but that does not wrap err: Unwrap, Cause, Is and
+// As do not reach the errors of err's chain. A package returning the errors
+// of its dependencies through Opaque does not make their types and
+// sentinel errors part of its API. If err is nil, Opaque returns nil.
+func Opaque(err error) error {
+	if err == nil {
+		return nil
+	}
+	o := opaque{msg: err.Error(), err: err}
+	if hasStack(err) {
+		return &opaqueStack{o}
+	}
+	return &o
+}
+
+// opaque is the error Opaque returns for errors without a stack trace.
+type opaque struct {
+	msg string
+	err error // only used to print the stack trace of the error
+}
+
+func (o *opaque) Error() string { return o.msg }
+
+func (o *opaque) Format(s fmt.State, verb rune) { formatError(s, verb, o) }
+
+func (*opaque) own() {}
+
+func (o *opaque) writeStack(w io.Writer, opts frameOptions) {
+	writeStackOf(w, o.err, opts)
+}
+
+// opaqueStack is the error Opaque returns for errors with a stack trace.
+type opaqueStack struct {
+	opaque
+}
+
+func (o *opaqueStack) StackTrace() StackTrace {
+	st, _ := stackTraceOf(o.err)
+	return st
+}
+
+func (o *opaqueStack) Format(s fmt.State, verb rune) { formatError(s, verb, o) }
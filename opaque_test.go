@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestOpaque(t *testing.T) {
+	err := Wrap(io.EOF, "read")
+	o := Opaque(err)
+	if got, want := o.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%+v", o), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if Is(o, io.EOF) || Unwrap(o) != nil || Cause(o) != o {
+		t.Errorf("Opaque: %v wraps its error", o)
+	}
+	st, ok := stackTraceOf(o)
+	if want, _ := stackTraceOf(err); !ok || len(st) != len(want) || st[0] != want[0] {
+		t.Errorf("Opaque: got stack trace %v, want %v", st, want)
+	}
+
+	o = Opaque(fmt.Errorf("read: %w", io.EOF))
+	if hasStack(o) || Is(o, io.EOF) || fmt.Sprintf("%+v", o) != "read: EOF" {
+		t.Errorf("Opaque: got %+v for an error without stack trace", o)
+	}
+	if Opaque(nil) != nil {
+		t.Errorf("Opaque(nil): got non-nil error")
+	}
+}
@@ -0,0 +1,91 @@
+package errors
+
+import "fmt"
+
+// An Option annotates the error created by New or Wrap, so that a single
+// call can set its code, kind, fields and stack trace policy rather than
+// chaining annotations.
+type Option func(o *options)
+
+// options holds the annotations set by Options.
+type options struct {
+	code    string
+	kind    Kind
+	fields  []Field
+	noStack bool
+}
+
+// newOptions returns the annotations set by opts, or nil if there are
+// none, so that constructors called without options do not allocate them.
+func newOptions(opts []Option) *options {
+	if len(opts) == 0 {
+		return nil
+	}
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// stackless reports whether o disables recording a stack trace.
+func (o *options) stackless() bool {
+	return o != nil && o.noStack
+}
+
+// annotate returns err annotated as set by o.
+func (o *options) annotate(err error) error {
+	if o == nil {
+		return err
+	}
+	if len(o.fields) > 0 {
+		err = &withFields{err, o.fields}
+	}
+	if o.kind != "" {
+		err = &withKind{err, o.kind}
+	}
+	if o.code != "" {
+		err = &withCode{err, intern(o.code)}
+	}
+	return err
+}
+
+// Code sets the code of the error; see WithCode.
+func Code(code string) Option {
+	return func(o *options) { o.code = code }
+}
+
+// OfKind sets the kind of the error; see WithKind.
+func OfKind(kind Kind) Option {
+	return func(o *options) { o.kind = kind }
+}
+
+// Labels adds fields to the error; see WithFields.
+func Labels(fields ...Field) Option {
+	return func(o *options) { o.fields = append(o.fields, fields...) }
+}
+
+// Fieldsf adds fields to the error from alternating keys and values, such
+// as Fieldsf("user", id, "attempt", n). Keys that are not strings are
+// formatted with %v; a key without a value is given a nil one.
+func Fieldsf(keysAndValues ...interface{}) Option {
+	return func(o *options) {
+		for i := 0; i < len(keysAndValues); i += 2 {
+			key, ok := keysAndValues[i].(string)
+			if !ok {
+				key = fmt.Sprint(keysAndValues[i])
+			}
+			var value interface{}
+			if i+1 < len(keysAndValues) {
+				value = keysAndValues[i+1]
+			}
+			o.fields = append(o.fields, Field{key, value})
+		}
+	}
+}
+
+// NoStack disables recording a stack trace; see NewNoStack and
+// WrapNoStack.
+func NoStack() Option {
+	return func(o *options) { o.noStack = true }
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	const notFound Kind = "not found"
+	tests := []struct {
+		err    error
+		msg    string
+		code   string
+		kind   Kind
+		fields []Field
+		stack  bool
+	}{
+		{New("new"), "new", "", "", nil, true},
+		{New("new", Code("E1"), OfKind(notFound)), "new", "E1", notFound, nil, true},
+		{New("new", Fieldsf("user", 7, "attempt"), NoStack()), "new", "", "", []Field{{"user", 7}, {"attempt", nil}}, false},
+		{New("new", Labels(Label("k", 1)), Fieldsf(2, "two")), "new", "", "", []Field{{"k", 1}, {"2", "two"}}, true},
+		{Wrap(io.EOF, "wrap", Code("E2")), "wrap: EOF", "E2", "", nil, true},
+		{Wrap(io.EOF, "wrap", NoStack(), OfKind(notFound)), "wrap: EOF", "", notFound, nil, false},
+	}
+	for i, tt := range tests {
+		if got := tt.err.Error(); got != tt.msg {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.msg)
+		}
+		if got := CodeOf(tt.err); got != tt.code {
+			t.Errorf("test %d: CodeOf: got %q, want %q", i+1, got, tt.code)
+		}
+		if got := KindOf(tt.err); got != tt.kind {
+			t.Errorf("test %d: KindOf: got %q, want %q", i+1, got, tt.kind)
+		}
+		if got := Fields(tt.err); !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("test %d: Fields: got %v, want %v", i+1, got, tt.fields)
+		}
+		st, ok := stackTraceOf(tt.err)
+		if ok != tt.stack || ok && st[0].Name() != "github.com/pkg/errors.TestOptions" {
+			t.Errorf("test %d: got stack trace %v, want one: %t", i+1, st, tt.stack)
+		}
+		if Depth(tt.err) != len(Messages(tt.err)) || Depth(tt.err) > 2 {
+			t.Errorf("test %d: got depth %d", i+1, Depth(tt.err))
+		}
+	}
+	if Wrap(nil, "wrap", Code("E1")) != nil {
+		t.Errorf("Wrap(nil): got non-nil error")
+	}
+}
@@ -0,0 +1,32 @@
+package errors
+
+// origin returns the frame the innermost error with a stack trace in err's
+// chain was created at.
+func origin(err error) (Frame, bool) {
+	var st StackTrace
+	for n := 0; err != nil && n < maxChainDepth; n, err = n+1, Unwrap(err) {
+		if s, ok := err.(interface{ StackTrace() StackTrace }); ok {
+			if t := s.StackTrace(); len(t) > 0 {
+				st = t
+			}
+		}
+	}
+	if len(st) == 0 {
+		return 0, false
+	}
+	return st[0], true
+}
+
+// SameOrigin reports whether the innermost stack traces recorded in the
+// chains of a and b start at the same call site, that is whether a and b,
+// whatever their messages, were created by the same line of code. Call
+// sites are compared by Frame.Key. SameOrigin returns false if either
+// chain has no stack trace.
+func SameOrigin(a, b error) bool {
+	fa, ok := origin(a)
+	if !ok {
+		return false
+	}
+	fb, ok := origin(b)
+	return ok && fa.Key() == fb.Key()
+}
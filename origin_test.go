@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func newAt(id int) error { return Errorf("error %d", id) }
+
+func TestSameOrigin(t *testing.T) {
+	a, b := newAt(1), newAt(2)
+	c := New("elsewhere")
+	first := Wrap(io.EOF, "first")
+	second := Wrap(io.EOF, "second")
+
+	tests := []struct {
+		a, b error
+		want bool
+	}{
+		{a, b, true},
+		{Wrap(a, "wrapped"), WithMessage(b, "other"), true},
+		{fmt.Errorf("std: %w", a), b, true},
+		{a, c, false},
+		{first, second, false},
+		{first, WithMessage(first, "again"), true},
+		{a, io.EOF, false},
+		{io.EOF, io.EOF, false},
+		{nil, a, false},
+	}
+	for i, tt := range tests {
+		if got := SameOrigin(tt.a, tt.b); got != tt.want {
+			t.Errorf("test %d: SameOrigin(%v, %v): got %v, want %v", i+1, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Recover converts a panic into an error stored in *err. It must be called
+// directly by a deferred statement:
+//
+//	func run() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+//
+// The error records the panic value, reported by IsPanic, and the stack
+// trace of the goroutine at the point it panicked rather than at the point
+// it recovered. If the function did not panic, *err is left unchanged.
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = FromPanic(v)
+	}
+}
+
+// FromPanic converts the panic value v, as returned by recover, into an
+// error recording the stack trace of the goroutine at the point it
+// panicked, if called while panicking, or at the point FromPanic is called
+// otherwise. The message of the error is that of v if it is an error, v if
+// it is a string, the result of its String method if it is a fmt.Stringer
+// and v formatted with %v otherwise, prefixed with "panic: ". If v is an
+// error, it is the cause of the returned error. If v is nil, FromPanic
+// returns nil. If v is an error converted from a panic already, FromPanic
+// returns it as is, and if v was raised by Repanic or Must, FromPanic
+// returns the error passed to them.
+func FromPanic(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	if p, ok := v.(errorPanic); ok {
+		return p.err
+	}
+	if err, ok := v.(error); ok && IsPanic(err) {
+		return err
+	}
+	return &withStack{&PanicError{v}, panicStack(), currentGoroutine()}
+}
+
+// Repanic panics with err if it was converted from a panic by Recover or
+// FromPanic, and does nothing otherwise. It lets a worker pool recover the
+// panics of its workers, ship them as errors to the code coordinating
+// them, and raise them again there:
+//
+//	errors.Repanic(err)
+//
+// Recovering the panic Repanic raises, with Recover or FromPanic, returns
+// err unchanged, with the panic value and the stack trace of the point the
+// worker panicked. If the panic is not recovered, the program crashes
+// printing err with %+v, and so the stack trace of the worker.
+func Repanic(err error) {
+	if IsPanic(err) {
+		panic(errorPanic{err})
+	}
+}
+
+// IsPanic reports whether any error in err's chain was converted from a
+// panic by Recover or FromPanic.
+func IsPanic(err error) bool {
+	return find(err, func(err error) bool {
+		_, ok := err.(*PanicError)
+		return ok
+	})
+}
+
+// PanicError is the error a recovered panic is converted to by Recover and
+// FromPanic, which annotate it with the stack trace of the point the
+// goroutine panicked. It can be retrieved with As:
+//
+//	var p *errors.PanicError
+//	if errors.As(err, &p) {
+//		log.Printf("panicked with %#v", p.Value)
+//	}
+type PanicError struct {
+	// Value is the value the goroutine panicked with.
+	Value interface{}
+}
+
+func (p *PanicError) Error() string {
+	switch v := p.Value.(type) {
+	case error:
+		return "panic: " + v.Error()
+	case string:
+		return "panic: " + v
+	case fmt.Stringer:
+		return "panic: " + v.String()
+	default:
+		return fmt.Sprintf("panic: %v", v)
+	}
+}
+
+// Unwrap returns the panic value if it is an error.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+func (p *PanicError) Format(s fmt.State, verb rune) { formatError(s, verb, p) }
+
+func (*PanicError) own() {}
+
+// panicStack returns the stack trace of the panicking goroutine starting at
+// the first frame outside the runtime that panicked, when called by a
+// function deferred while panicking, or the stack of the caller of the
+// caller of panicStack otherwise. The frames of the runtime are skipped so
+// that the stack trace of a runtime error, such as a nil pointer
+// dereference, starts at the faulty code. panicStack returns a nil stack
+// if stack capture is disabled.
+func panicStack() stack {
+	if !captureEnabled() {
+		return nil
+	}
+	var pcs [maxDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	st := pcs[:n]
+	for i, pc := range st {
+		if funcName(pc) == "runtime.gopanic" {
+			st = st[i+1:]
+			for len(st) > 0 && pkgname(funcName(st[0])) == "runtime" {
+				st = st[1:]
+			}
+			break
+		}
+	}
+	return append(stack(nil), st...)
+}
+
+// funcName returns the name of the function of the return address pc.
+func funcName(pc uintptr) string {
+	if fn := runtime.FuncForPC(pc - 1); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// errorPanic is the value Must and its variants, and Repanic, panic with.
+// It is an error wrapping the error they were passed, whose message is that
+// error printed with %+v, so that the runtime prints the stack trace of the
+// error if the panic is not recovered.
+type errorPanic struct {
+	err error
+}
+
+func (p errorPanic) Error() string { return fmt.Sprintf("%+v", p.err) }
+
+func (p errorPanic) Unwrap() error { return p.err }
+
+// mustFail panics with err, annotated with the stack trace of the caller of
+// the function calling mustFail if it has none.
+func mustFail(err error) {
+	if captureEnabled() && !hasStack(err) {
+		err = &withStack{err, callers(1), currentGoroutine()}
+	}
+	panic(errorPanic{err})
+}
@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func panics(v interface{}) (err error) {
+	defer Recover(&err)
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	err := panics("boom")
+	if got, want := err.Error(), "panic: boom"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !IsPanic(err) || !IsPanic(Wrap(err, "wrapped")) {
+		t.Errorf("IsPanic(%v): got false, want true", err)
+	}
+	testFormatRegexp(t, 0, err, "%+v", "panic: boom\n"+
+		"github.com/pkg/errors.panics\n"+
+		"\t.+/panic_test.go:10\n"+
+		"github.com/pkg/errors.TestRecover\n"+
+		"\t.+/panic_test.go:14")
+
+	err = func() (err error) {
+		defer Recover(&err)
+		var m map[string]int
+		m["nil"] = 1
+		return nil
+	}()
+	if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Line() != 30 {
+		t.Errorf("runtime error: got stack trace %v, want one starting at panic_test.go:30", st)
+	}
+
+	err = panics(io.EOF)
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(%v, io.EOF): got false, want true", err)
+	}
+
+	func() {
+		defer Recover(&err)
+	}()
+	if !Is(err, io.EOF) {
+		t.Errorf("Recover without a panic changed err to %v", err)
+	}
+
+	if IsPanic(New("not a panic")) || IsPanic(nil) {
+		t.Errorf("IsPanic: got true for an error that is not a panic")
+	}
+}
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+func TestFromPanic(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{io.EOF, "panic: EOF"},
+		{"boom", "panic: boom"},
+		{stringer{}, "panic: stringer"},
+		{42, "panic: 42"},
+		{[]int{1, 2}, "panic: [1 2]"},
+	}
+	for i, tt := range tests {
+		err := FromPanic(tt.v)
+		if got := err.Error(); got != tt.want {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, tt.want)
+		}
+		if !IsPanic(err) {
+			t.Errorf("test %d: IsPanic(%v): got false, want true", i+1, err)
+		}
+		if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Name() != "github.com/pkg/errors.TestFromPanic" {
+			t.Errorf("test %d: got stack trace %v, want one starting in TestFromPanic", i+1, st)
+		}
+	}
+	if err := FromPanic(nil); err != nil {
+		t.Errorf("FromPanic(nil): got %v, want nil", err)
+	}
+
+	err := func() (err error) {
+		defer func() { err = FromPanic(recover()) }()
+		panic("recovered")
+	}()
+	if st, _ := stackTraceOf(err); len(st) == 0 || st[0].Line() != 87 {
+		t.Errorf("recovered: got stack trace %v, want one starting at panic_test.go:87", st)
+	}
+}
+
+func TestRepanic(t *testing.T) {
+	worker := panics("worker")
+	Repanic(nil)
+	Repanic(io.EOF)
+
+	err := func() (err error) {
+		defer Recover(&err)
+		Repanic(Wrap(worker, "shipped"))
+		return nil
+	}()
+	if got, want := err.Error(), "shipped: panic: worker"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	var p *PanicError
+	if !As(err, &p) || p.Value != "worker" {
+		t.Fatalf("As(%v, *PanicError): got %#v", err, p)
+	}
+	got, _ := stackTraceOf(err)
+	want, _ := stackTraceOf(worker)
+	if len(got) == 0 || got[0] != want[0] {
+		t.Errorf("Repanic: got stack trace %v, want that of the worker %v", got, want)
+	}
+}
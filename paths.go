@@ -0,0 +1,286 @@
+package errors
+
+import (
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PathOptions controls how the source file paths of frames are rendered
+// by %+v, MarshalText and Frame.URL. Frame.File always returns the path
+// as recorded by the compiler.
+//
+// Unless RawPaths is set, paths are first normalised to forward slashes and
+// Windows drive letters are rewritten to a leading path element, so that
+// C:\gopath\src\app\main.go is rendered as /c/gopath/src/app/main.go. The
+// other options apply to normalised paths.
+type PathOptions struct {
+	// RawPaths disables the normalisation of paths.
+	RawPaths bool
+
+	// TrimGOPATH strips the $GOPATH/src/ and module cache prefixes, so
+	// that paths read like import paths, for example
+	// "github.com/pkg/errors/stack.go".
+	TrimGOPATH bool
+
+	// TrimGOROOT strips the $GOROOT/src/ prefix from the paths of the
+	// standard library.
+	TrimGOROOT bool
+
+	// Rewrites are applied, in order, after trimming. The first rule
+	// whose Prefix matches the path has that prefix replaced by Replace.
+	// Rules can be used to map paths to a repository relative form,
+	// whether or not the binary was built with -trimpath.
+	Rewrites []PathRewrite
+
+	// URLTemplate is used by Frame.URL to link frames to their source.
+	// The placeholders {file} and {line} are replaced by the rendered
+	// path and the line number of the frame, for example
+	// "https://github.com/org/repo/blob/master/{file}#L{line}".
+	URLTemplate string
+
+	// ModuleURLs maps module paths, such as "github.com/org/repo", to the
+	// templates Frame.URL links the frames of their packages with,
+	// instead of URLTemplate, for example
+	// "https://github.com/org/repo/blob/{commit}/{file}#L{line}". There,
+	// {file} is replaced by the path of the source file relative to the
+	// root of the module, whatever the directory it was built in, and
+	// {commit} by the revision of the module: the commit of the main
+	// module recorded in the build information of the program, or the
+	// version of a dependency, or its commit for a pseudo-version. The
+	// frames of package main belong to the main module.
+	ModuleURLs map[string]string
+}
+
+// PathRewrite replaces the leading Prefix of a path with Replace.
+type PathRewrite struct {
+	Prefix  string
+	Replace string
+}
+
+// pathRules is the compiled form of a PathOptions.
+type pathRules struct {
+	opts     PathOptions // the options compiled
+	raw      bool
+	trims    []string
+	rewrites []PathRewrite
+	url      string
+	modules  []moduleURL // longest path first
+}
+
+// moduleURL is the URL template of the frames of a module.
+type moduleURL struct {
+	path, url string
+}
+
+// SetPathOptions sets how source file paths are rendered. The GOPATH is
+// resolved once, when SetPathOptions is called.
+func SetPathOptions(opts PathOptions) {
+	r := compilePaths(opts)
+	updateConfig(func(c *config) { c.paths = r })
+}
+
+// compilePaths returns the rules implementing opts.
+func compilePaths(opts PathOptions) *pathRules {
+	opts.Rewrites = append([]PathRewrite(nil), opts.Rewrites...)
+	urls := opts.ModuleURLs
+	opts.ModuleURLs = nil
+	r := &pathRules{
+		raw:      opts.RawPaths,
+		rewrites: opts.Rewrites,
+		url:      opts.URLTemplate,
+	}
+	if len(urls) > 0 {
+		opts.ModuleURLs = make(map[string]string, len(urls))
+		for m, url := range urls {
+			m = strings.TrimSuffix(m, "/")
+			opts.ModuleURLs[m] = url
+			r.modules = append(r.modules, moduleURL{m, url})
+		}
+		sort.Slice(r.modules, func(i, j int) bool { return len(r.modules[i].path) > len(r.modules[j].path) })
+	}
+	r.opts = opts
+	if opts.TrimGOPATH {
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			gopath = build.Default.GOPATH
+		}
+		for _, p := range filepath.SplitList(gopath) {
+			p = r.normalize(p)
+			r.trims = append(r.trims, p+"/pkg/mod/", p+"/src/")
+		}
+	}
+	if opts.TrimGOROOT {
+		r.trims = append(r.trims, r.normalize(runtime.GOROOT())+"/src/")
+	}
+	return r
+}
+
+// normalize returns file normalised unless raw paths are requested.
+func (r *pathRules) normalize(file string) string {
+	if r != nil && r.raw {
+		return file
+	}
+	return normalizePath(file)
+}
+
+// normalizePath converts backslashes in file to forward slashes and a
+// leading Windows drive letter, as in C:\dir, to a path element, as in /c/dir.
+func normalizePath(file string) string {
+	file = strings.Replace(file, "\\", "/", -1)
+	if len(file) >= 2 && file[1] == ':' && (len(file) == 2 || file[2] == '/') {
+		if d := file[0] | 0x20; 'a' <= d && d <= 'z' {
+			file = "/" + string(d) + file[2:]
+		}
+	}
+	return file
+}
+
+// rewrite applies the normalisation, trimming and rewrite rules to file.
+func (r *pathRules) rewrite(file string) string {
+	file = r.normalize(file)
+	if r == nil {
+		return file
+	}
+	for _, p := range r.trims {
+		if strings.HasPrefix(file, p) {
+			file = file[len(p):]
+			break
+		}
+	}
+	for _, rw := range r.rewrites {
+		if strings.HasPrefix(file, rw.Prefix) {
+			file = rw.Replace + file[len(rw.Prefix):]
+			break
+		}
+	}
+	return file
+}
+
+// path returns the path of the source file of this Frame as rendered
+// according to the configured PathOptions.
+func (f Frame) path() string {
+	return loadConfig().paths.rewrite(f.File())
+}
+
+// URL returns a link to the source of this Frame built from the template
+// of its module in the ModuleURLs set with SetPathOptions or, failing that,
+// from the URLTemplate set. It returns the empty string if no template is
+// set or the frame is unknown.
+func (f Frame) URL() string {
+	r := loadConfig().paths
+	if r == nil || f.Name() == "unknown" {
+		return ""
+	}
+	if url, ok := r.moduleURL(f); ok {
+		return url
+	}
+	if r.url == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{file}", r.rewrite(f.File()),
+		"{line}", strconv.Itoa(f.Line()),
+	).Replace(r.url)
+}
+
+// moduleURL returns the link to the source of f built from the template of
+// its module, if it has one.
+func (r *pathRules) moduleURL(f Frame) (string, bool) {
+	pkg := pkgname(f.Name())
+	main := pkg == "main"
+	if main {
+		pkg = mainModule()
+	}
+	for _, m := range r.modules {
+		if pkg != m.path && !strings.HasPrefix(pkg, m.path+"/") {
+			continue
+		}
+		file := path.Base(normalizePath(f.File()))
+		if main {
+			file = moduleFile(normalizePath(f.File()), m.path)
+		} else if dir := strings.TrimPrefix(pkg[len(m.path):], "/"); dir != "" {
+			file = dir + "/" + file
+		}
+		return strings.NewReplacer(
+			"{file}", file,
+			"{line}", strconv.Itoa(f.Line()),
+			"{commit}", moduleRevision(m.path),
+		).Replace(m.url), true
+	}
+	return "", false
+}
+
+// moduleFile returns the path of file relative to the root of module, found
+// in paths such as $GOPATH/src/<module>/<file>, the paths of the module
+// cache, $GOMODCACHE/<module>@<version>/<file>, and the paths of programs
+// built with -trimpath, <module>/<file>, or the base name of file if none
+// match.
+func moduleFile(file, module string) string {
+	for _, sep := range []string{"/", "@"} {
+		i := strings.Index("/"+file, "/"+module+sep)
+		if i < 0 {
+			continue
+		}
+		rest := file[i+len(module)+1:]
+		if sep == "@" {
+			j := strings.IndexByte(rest, '/')
+			if j < 0 {
+				continue
+			}
+			rest = rest[j+1:]
+		}
+		return rest
+	}
+	return path.Base(file)
+}
+
+var revisions struct {
+	once sync.Once
+	main string            // the path of the main module
+	m    map[string]string // revision by module path
+}
+
+// moduleRevision returns the revision of module recorded in the build
+// information of the program, as documented by PathOptions.ModuleURLs, or
+// the empty string if it is unknown.
+func moduleRevision(module string) string {
+	revisions.once.Do(func() {
+		revisions.m = make(map[string]string)
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		revisions.main = bi.Main.Path
+		revisions.m[bi.Main.Path] = vcsRevision(bi)
+		for _, d := range bi.Deps {
+			if d.Replace != nil {
+				d = d.Replace
+			}
+			revisions.m[d.Path] = versionRevision(d.Version)
+		}
+	})
+	if module == mainModule() {
+		module = revisions.main
+	}
+	return revisions.m[module]
+}
+
+// versionRevision returns the commit of a pseudo-version, such as
+// v0.0.0-20191109021931-daa7c04131f5, or the version otherwise.
+func versionRevision(version string) string {
+	version = strings.TrimSuffix(version, "+incompatible")
+	if i := strings.LastIndexByte(version, '-'); i >= 0 && len(version)-i-1 == 12 {
+		if _, err := strconv.ParseUint(version[i+1:], 16, 64); err == nil {
+			return version[i+1:]
+		}
+	}
+	return version
+}
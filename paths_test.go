@@ -0,0 +1,170 @@
+package errors
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSetPathOptionsTrim(t *testing.T) {
+	defer SetPathOptions(PathOptions{})
+	defer setenv("GOPATH", "/home/gopher/go:/opt/go")()
+
+	SetPathOptions(PathOptions{TrimGOPATH: true})
+	tests := []struct {
+		file, want string
+	}{
+		{"/home/gopher/go/src/github.com/pkg/errors/stack.go", "github.com/pkg/errors/stack.go"},
+		{"/opt/go/src/example.com/x/x.go", "example.com/x/x.go"},
+		{"/home/gopher/go/pkg/mod/example.com/y@v1.2.3/y.go", "example.com/y@v1.2.3/y.go"},
+		{"github.com/pkg/errors/stack.go", "github.com/pkg/errors/stack.go"}, // -trimpath
+		{"/elsewhere/src/x.go", "/elsewhere/src/x.go"},
+	}
+	for _, tt := range tests {
+		if got := loadConfig().paths.rewrite(tt.file); got != tt.want {
+			t.Errorf("rewrite(%q): got %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSetPathOptionsRewrite(t *testing.T) {
+	defer SetPathOptions(PathOptions{})
+
+	dir := path.Dir(initpc.File()) + "/"
+	SetPathOptions(PathOptions{
+		Rewrites:    []PathRewrite{{Prefix: "/nowhere/", Replace: "x/"}, {Prefix: dir, Replace: "repo/"}},
+		URLTemplate: "https://example.com/blob/master/{file}#L{line}",
+	})
+
+	testFormatRegexp(t, 0, initpc, "%+v", "github.com/pkg/errors.init\n\trepo/stack_test.go:\\d+")
+
+	text, err := initpc.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `^github.com/pkg/errors\.init repo/stack_test.go:\d+$`; !regexp.MustCompile(want).Match(text) {
+		t.Errorf("MarshalText: got %q, want %q", text, want)
+	}
+
+	want := "https://example.com/blob/master/repo/stack_test.go#L" + strconv.Itoa(initpc.Line())
+	if got := initpc.URL(); got != want {
+		t.Errorf("URL(): got %q, want %q", got, want)
+	}
+	if got := Frame(0).URL(); got != "" {
+		t.Errorf("URL() of unknown frame: got %q, want empty", got)
+	}
+	if got := initpc.File(); strings.HasPrefix(got, "repo/") {
+		t.Errorf("File(): got %q, want the path as recorded by the compiler", got)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		file, want string
+	}{
+		{"/home/gopher/go/src/app/main.go", "/home/gopher/go/src/app/main.go"},
+		{`C:\gopath\src\app\main.go`, "/c/gopath/src/app/main.go"},
+		{`d:\x.go`, "/d/x.go"},
+		{"C:/gopath/src/app/main.go", "/c/gopath/src/app/main.go"},
+		{`app\main.go`, "app/main.go"},
+		{"github.com/pkg/errors/stack.go", "github.com/pkg/errors/stack.go"},
+		{"unknown", "unknown"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizePath(tt.file); got != tt.want {
+			t.Errorf("normalizePath(%q): got %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSetPathOptionsRawPaths(t *testing.T) {
+	defer SetPathOptions(PathOptions{})
+
+	const file = `C:\gopath\src\app\main.go`
+	if got, want := loadConfig().paths.rewrite(file), "/c/gopath/src/app/main.go"; got != want {
+		t.Errorf("rewrite(%q): got %q, want %q", file, got, want)
+	}
+	SetPathOptions(PathOptions{Rewrites: []PathRewrite{{Prefix: "/c/gopath/src/", Replace: ""}}})
+	if got, want := loadConfig().paths.rewrite(file), "app/main.go"; got != want {
+		t.Errorf("rewrite(%q): got %q, want %q", file, got, want)
+	}
+	SetPathOptions(PathOptions{RawPaths: true})
+	if got := loadConfig().paths.rewrite(file); got != file {
+		t.Errorf("rewrite(%q) with RawPaths: got %q, want it unchanged", file, got)
+	}
+}
+
+func TestSetPathOptionsModuleURLs(t *testing.T) {
+	defer SetPathOptions(PathOptions{})
+
+	SetPathOptions(PathOptions{
+		ModuleURLs: map[string]string{
+			"github.com":            "https://example.com/wrong/{file}",
+			"github.com/pkg/errors": "https://example.com/errors/blob/{commit}/{file}#L{line}",
+		},
+		URLTemplate: "https://example.com/other/{file}",
+	})
+	want := "https://example.com/errors/blob/" + moduleRevision("github.com/pkg/errors") + "/stack_test.go#L" + strconv.Itoa(initpc.Line())
+	if got := initpc.URL(); got != want {
+		t.Errorf("URL(): got %q, want %q", got, want)
+	}
+	if got := CurrentConfig().Paths.ModuleURLs["github.com/pkg/errors"]; got == "" {
+		t.Errorf("CurrentConfig: got no URL template for the module")
+	}
+
+	st, _ := stackTraceOf(New("x"))
+	last := st[len(st)-1] // runtime.goexit
+	if got := last.URL(); !strings.HasPrefix(got, "https://example.com/other/") {
+		t.Errorf("URL() of %s: got %q, want the URLTemplate", last.Name(), got)
+	}
+}
+
+func TestModuleFile(t *testing.T) {
+	tests := []struct {
+		file, module, want string
+	}{
+		{"/go/src/github.com/org/repo/cmd/app/main.go", "github.com/org/repo", "cmd/app/main.go"},
+		{"/go/pkg/mod/github.com/org/repo@v1.2.3/cmd/main.go", "github.com/org/repo", "cmd/main.go"},
+		{"github.com/org/repo/main.go", "github.com/org/repo", "main.go"},
+		{"/home/me/repo/cmd/main.go", "github.com/org/repo", "main.go"},
+		{"/go/src/github.com/org/repository/main.go", "github.com/org/repo", "main.go"},
+	}
+	for i, tt := range tests {
+		if got := moduleFile(tt.file, tt.module); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestVersionRevision(t *testing.T) {
+	tests := []struct{ version, want string }{
+		{"v1.2.3", "v1.2.3"},
+		{"v0.0.0-20191109021931-daa7c04131f5", "daa7c04131f5"},
+		{"v2.0.0+incompatible", "v2.0.0"},
+		{"v1.0.0-rc1", "v1.0.0-rc1"},
+	}
+	for i, tt := range tests {
+		if got := versionRevision(tt.version); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+// setenv sets the environment variable key to value, and returns the
+// function restoring its previous value, like t.Setenv, which requires
+// Go 1.17.
+func setenv(key, value string) (restore func()) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package errors
+
+import "fmt"
+
+// Pipeline runs named stages in order, stopping at the first that fails.
+// The error of a failed stage is wrapped, as by Wrap, with the message
+// "stage " followed by its name, so that the errors of nested pipelines
+// read like "stage decode: stage validate: missing id". The name and index
+// of the stage are retrieved with StageOf. The zero Pipeline has no stages.
+type Pipeline struct {
+	stages []pipelineStage
+}
+
+type pipelineStage struct {
+	name string
+	fn   func() error
+}
+
+// Stage appends a stage named name running fn to p, and returns p so that
+// calls can be chained.
+func (p *Pipeline) Stage(name string, fn func() error) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{name, fn})
+	return p
+}
+
+// Run runs the stages of p in order and returns the wrapped error of the
+// first that fails, or nil if they all succeed.
+func (p *Pipeline) Run() error {
+	for i, s := range p.stages {
+		if err := s.fn(); err != nil {
+			return &withStage{wrap(err, intern("stage "+s.name), nil), s.name, i}
+		}
+	}
+	return nil
+}
+
+// StageOf returns the name and index of the outermost pipeline stage that
+// failed with err, and reports whether err failed in a stage at all.
+func StageOf(err error) (name string, index int, ok bool) {
+	find(err, func(err error) bool {
+		var w *withStage
+		w, ok = err.(*withStage)
+		if ok {
+			name, index = w.name, w.index
+		}
+		return ok
+	})
+	return name, index, ok
+}
+
+// withStage annotates the error of a pipeline stage with its name and
+// index.
+type withStage struct {
+	error
+	name  string
+	index int
+}
+
+func (w *withStage) Cause() error { return w.error }
+
+func (w *withStage) Unwrap() error { return w.error }
+
+func (w *withStage) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withStage) own() {}
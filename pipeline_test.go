@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	var ran []string
+	stage := func(name string, err error) func() error {
+		return func() error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+	inner := new(Pipeline).
+		Stage("parse", stage("parse", nil)).
+		Stage("validate", stage("validate", io.ErrUnexpectedEOF))
+	p := new(Pipeline).
+		Stage("read", stage("read", nil)).
+		Stage("decode", inner.Run).
+		Stage("write", stage("write", nil))
+
+	err := p.Run()
+	if got, want := err.Error(), "stage decode: stage validate: unexpected EOF"; got != want {
+		t.Errorf("Run: got %q, want %q", got, want)
+	}
+	if got, want := len(ran), 3; got != want {
+		t.Errorf("Run: ran %v, want the stages up to validate", ran)
+	}
+	if !Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Is(%v, io.ErrUnexpectedEOF): got false, want true", err)
+	}
+	if name, index, ok := StageOf(err); name != "decode" || index != 1 || !ok {
+		t.Errorf("StageOf: got %q, %d, %v, want \"decode\", 1, true", name, index, ok)
+	}
+	if name, index, ok := StageOf(At(err, 1)); name != "validate" || index != 1 || !ok {
+		t.Errorf("StageOf inner: got %q, %d, %v, want \"validate\", 1, true", name, index, ok)
+	}
+	if got := Depth(err); got != 3 {
+		t.Errorf("Depth(%v): got %d, want 3", err, got)
+	}
+
+	if err := new(Pipeline).Stage("ok", stage("ok", nil)).Run(); err != nil {
+		t.Errorf("Run: got %v, want nil", err)
+	}
+	if _, _, ok := StageOf(io.EOF); ok {
+		t.Errorf("StageOf(io.EOF): got ok")
+	}
+}
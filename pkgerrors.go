@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SetPkgErrorsFormatting makes %+v print errors exactly as the errors of
+// github.com/pkg/errors print, for programs migrated from it whose log
+// processing expects its layout: the chain is printed innermost first,
+// each message on its own line followed by the stack trace recorded with
+// it, if any, one "\n<function>\n\t<file>:<line>" per frame, where the file
+// is the full path of the source file. The path options, frame formatter,
+// stack limit and the other settings changing how stack traces print do
+// not apply, except for the stack filter. pkg/errors formatting is
+// disabled by default.
+func SetPkgErrorsFormatting(enabled bool) {
+	updateConfig(func(c *config) { c.pkgErrorsFormat = enabled })
+}
+
+// writePkgErrors writes err in the %+v layout of github.com/pkg/errors.
+func writePkgErrors(w io.Writer, err error) {
+	writePkgErrorsWithin(w, err, maxChainDepth)
+}
+
+func writePkgErrorsWithin(w io.Writer, err error, n int) {
+	if n == 0 {
+		return
+	}
+	switch e := err.(type) {
+	case *withMessage:
+		// The stack trace recorded by Wrap follows its message, as the
+		// stack trace of the withStack wrapping the withMessage of the
+		// Wrap of pkg/errors does.
+		ws, ok := e.wrapStack()
+		if ok {
+			writePkgErrorsWithin(w, ws.error, n-1)
+		} else {
+			writePkgErrorsWithin(w, e.cause, n-1)
+		}
+		io.WriteString(w, "\n")
+		io.WriteString(w, e.message())
+		if ok {
+			writePkgErrorsStack(w, ws.StackTrace())
+		}
+	case *withStack:
+		writePkgErrorsWithin(w, e.error, n-1)
+		writePkgErrorsStack(w, e.StackTrace())
+	default:
+		if isAnnotation(err) {
+			writePkgErrorsWithin(w, Unwrap(err), n-1)
+			return
+		}
+		if _, ok := err.(own); !ok {
+			fmt.Fprintf(w, "%+v", err)
+			return
+		}
+		io.WriteString(w, err.Error())
+		if st, ok := err.(stackTracer); ok {
+			writePkgErrorsStack(w, st.StackTrace())
+		}
+	}
+}
+
+// writePkgErrorsStack writes st as github.com/pkg/errors prints stack
+// traces with %+v.
+func writePkgErrorsStack(w io.Writer, st StackTrace) {
+	for _, f := range st.visible() {
+		io.WriteString(w, "\n")
+		io.WriteString(w, f.Name())
+		io.WriteString(w, "\n\t")
+		io.WriteString(w, f.File())
+		io.WriteString(w, ":")
+		io.WriteString(w, strconv.Itoa(f.Line()))
+	}
+}
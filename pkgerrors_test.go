@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetPkgErrorsFormatting(t *testing.T) {
+	defer SetPkgErrorsFormatting(false)
+	defer SetPathOptions(PathOptions{})
+
+	// frames returns st in the layout of github.com/pkg/errors.
+	frames := func(st StackTrace) string {
+		var b strings.Builder
+		for _, f := range st {
+			fmt.Fprintf(&b, "\n%s\n\t%s:%d", f.Name(), f.File(), f.Line())
+		}
+		return b.String()
+	}
+
+	SetPkgErrorsFormatting(true)
+	SetPathOptions(PathOptions{TrimGOPATH: true})
+	inner := New("inner")
+	outer := Wrap(io.EOF, "outer")
+	innerStack, _ := stackTraceOf(inner)
+	outerStack, _ := stackTraceOf(outer)
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{inner, "inner" + frames(innerStack)},
+		{outer, "EOF\nouter" + frames(outerStack)},
+		{Wrap(inner, "outer"), "inner" + frames(innerStack) + "\nouter"},
+		{WithMessage(io.EOF, "read"), "EOF\nread"},
+		{WithCode(WithMessage(WithMessage(io.EOF, "b"), "a"), "E1"), "EOF\nb\na"},
+		{WithMessage(fmt.Errorf("open: %w", io.EOF), "load"), "open: EOF\nload"},
+	}
+	for i, tt := range tests {
+		if got := fmt.Sprintf("%+v", tt.err); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+	if got := fmt.Sprintf("%v", outer); got != "outer: EOF" {
+		t.Errorf("%%v: got %q, want %q", got, "outer: EOF")
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Pooled is the type of the errors returned by Get and GetWrap while stack
+// traces are captured: errors whose value, including the buffer their stack
+// trace is recorded in, is reused once released by Release. Pooled errors
+// are meant for hot paths, such as the request loop of a proxy, where the
+// cost of allocating an error for every failure is not acceptable; they are
+// opt-in and the errors returned by New and Wrap are never pooled.
+//
+// A pooled error must be released exactly once, by the code that got it,
+// when it is no longer needed, and must not be used, nor retained, in any
+// way afterwards: a released error is handed to the next caller of Get or
+// GetWrap, which changes its message, cause and stack trace. In particular
+// a pooled error must not be released while another goroutine, a logger
+// for example, may still be formatting it.
+type Pooled struct {
+	msg   string
+	cause error
+	pcs   [maxDepth]uintptr
+	n     int // number of program counters recorded in pcs
+}
+
+var pooled = sync.Pool{
+	New: func() interface{} { return new(Pooled) },
+}
+
+// Get returns a Pooled error with the supplied message, recording the stack
+// trace at the point Get was called, like New.
+func Get(message string) error {
+	p := pooled.Get().(*Pooled)
+	p.msg = message
+	return p.record()
+}
+
+// GetWrap returns a Pooled error annotating err with the supplied message
+// and the stack trace at the point GetWrap was called, like Wrap. If err is
+// nil, GetWrap returns nil.
+func GetWrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	p := pooled.Get().(*Pooled)
+	p.msg = message
+	p.cause = err
+	return p.record()
+}
+
+// record records the stack trace of the caller of the caller of record in
+// p, if it is sampled, and returns p. If stack traces are not captured,
+// record returns p as a pooledNoStack instead, which has no stack trace, so
+// that the errors wrapping it can record theirs, as for New.
+func (p *Pooled) record() error {
+	c := loadConfig()
+	if !capturing(c) {
+		return (*pooledNoStack)(p)
+	}
+	if !sampled(c) {
+		return p
+	}
+	pcs := p.pcs[:]
+	if c.singleFrame {
+		pcs = pcs[:1]
+	}
+	p.n = runtime.Callers(3, pcs)
+	return p
+}
+
+// Release returns err to the pool for reuse if it was returned by Get or
+// GetWrap, and does nothing otherwise, so that it can be called with any
+// error. err must not be used after Release returns.
+func Release(err error) {
+	switch p := err.(type) {
+	case *Pooled:
+		p.release()
+	case *pooledNoStack:
+		(*Pooled)(p).release()
+	}
+}
+
+func (p *Pooled) release() {
+	*p = Pooled{}
+	pooled.Put(p)
+}
+
+func (p *Pooled) Error() string {
+	if p.cause == nil {
+		return p.msg
+	}
+	return p.msg + ": " + p.cause.Error()
+}
+
+func (p *Pooled) Cause() error { return p.cause }
+
+func (p *Pooled) Unwrap() error { return p.cause }
+
+// StackTrace returns the stack trace recorded in p, which is empty if it
+// was not sampled. The StackTrace is a copy that can be retained after p
+// is released.
+func (p *Pooled) StackTrace() StackTrace {
+	return StackTraceFromPCs(p.pcs[:p.n])
+}
+
+func (p *Pooled) Format(s fmt.State, verb rune) { formatError(s, verb, p) }
+
+func (*Pooled) own() {}
+
+func (p *Pooled) writeStack(w io.Writer, o frameOptions) {
+	if p.n == 0 {
+		io.WriteString(w, "\n(stack trace not sampled)")
+		return
+	}
+	p.StackTrace().visible().writeFrames(w, o)
+}
+
+// pooledNoStack is a Pooled error recorded while stack traces were not
+// captured.
+type pooledNoStack Pooled
+
+func (p *pooledNoStack) Error() string { return (*Pooled)(p).Error() }
+
+func (p *pooledNoStack) Cause() error { return p.cause }
+
+func (p *pooledNoStack) Unwrap() error { return p.cause }
+
+func (p *pooledNoStack) Format(s fmt.State, verb rune) { formatError(s, verb, p) }
+
+func (*pooledNoStack) own() {}
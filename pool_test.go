@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	err := Get("pooled")
+	if got, want := err.Error(), "pooled"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if Unwrap(err) != nil {
+		t.Errorf("Unwrap(): got %v, want nil", Unwrap(err))
+	}
+	p, ok := err.(*Pooled)
+	if !ok {
+		t.Fatalf("Get: got %T, want *Pooled", err)
+	}
+	st := p.StackTrace()
+	if len(st) == 0 {
+		t.Fatalf("StackTrace(): got an empty stack trace")
+	}
+	if got, want := st[0].Name(), "github.com/pkg/errors.TestGet"; got != want {
+		t.Errorf("StackTrace()[0]: got %q, want %q", got, want)
+	}
+	Release(err)
+	if got := st[0].Name(); got != "github.com/pkg/errors.TestGet" {
+		t.Errorf("StackTrace()[0] after Release: got %q", got)
+	}
+}
+
+func TestGetWrap(t *testing.T) {
+	if err := GetWrap(nil, "no error"); err != nil {
+		t.Errorf("GetWrap(nil): got %#v, want nil", err)
+	}
+
+	err := GetWrap(io.EOF, "read")
+	defer Release(err)
+	if got, want := err.Error(), "read: EOF"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(%v, io.EOF): got false, want true", err)
+	}
+	testFormatRegexp(t, 0, err, "%+v", "read: EOF\n"+
+		"github.com/pkg/errors.TestGetWrap\n"+
+		"\t.+/pool_test.go:39")
+}
+
+func TestPooledNotSampled(t *testing.T) {
+	defer SetStackSampling(1)
+
+	SetStackSampling(0)
+	err := Get("pooled")
+	defer Release(err)
+	if got, want := fmt.Sprintf("%+v", err), "pooled\n(stack trace not sampled)"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+}
+
+func TestPooledNoStackCapture(t *testing.T) {
+	defer SetStackCapture(true)
+
+	SetStackCapture(false)
+	err := GetWrap(io.EOF, "read")
+	defer Release(err)
+	if HasStack(err) {
+		t.Errorf("HasStack: got true, want false")
+	}
+	if got, want := fmt.Sprintf("%+v", err), "read: EOF"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(%v, io.EOF): got false, want true", err)
+	}
+}
+
+func TestPooledAllocs(t *testing.T) {
+	Release(Get("warm up"))
+	allocs := testing.AllocsPerRun(100, func() {
+		Release(GetWrap(io.EOF, "read"))
+	})
+	if allocs != 0 {
+		t.Errorf("GetWrap and Release: got %v allocs, want 0", allocs)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package errors
+
+// raceEnabled reports whether the tests run with the race detector, whose
+// instrumentation allocates.
+const raceEnabled = true
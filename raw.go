@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SetRawFrames makes Frame.MarshalText, and so the text and JSON encodings
+// of frames and stack traces, emit the program counter of frames instead of
+// their function, file and line. Resolving these is the most expensive part
+// of encoding a stack trace; raw frames defer it to the consumer, which can
+// resolve them later with the executable, provided it is the same build:
+// encode BuildID along with raw frames to check it. Raw frames are disabled
+// by default.
+//
+// As executables are usually loaded at a different address on every run,
+// a raw frame is the offset of its program counter from the entry of
+// runtime.Callers, such as "0x4a5b1c", or "-0x2f10" for functions before
+// it. The address of a frame in the executable is that offset added to the
+// address of runtime.Callers, as printed by "go tool nm", and can be
+// resolved with "go tool addr2line".
+func SetRawFrames(enabled bool) {
+	updateConfig(func(c *config) { c.rawFrames = enabled })
+}
+
+// UnmarshalText sets f to the frame encoded by MarshalText while raw frames
+// are enabled; see SetRawFrames. The frame can only be resolved in a process
+// running the same build as the executable it was encoded by.
+func (f *Frame) UnmarshalText(text []byte) error {
+	s := string(text)
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("errors: invalid raw frame %q", text)
+	}
+	off, err := strconv.ParseUint(s[2:], 16, 64)
+	if err != nil {
+		return fmt.Errorf("errors: invalid raw frame %q", text)
+	}
+	if neg {
+		*f = Frame(rawBase() - uintptr(off))
+	} else {
+		*f = Frame(rawBase() + uintptr(off))
+	}
+	return nil
+}
+
+// rawText returns the text encoding of f while raw frames are enabled.
+func (f Frame) rawText() []byte {
+	base := rawBase()
+	if uintptr(f) < base {
+		return strconv.AppendUint([]byte("-0x"), uint64(base-uintptr(f)), 16)
+	}
+	return strconv.AppendUint([]byte("0x"), uint64(uintptr(f)-base), 16)
+}
+
+// rawBase returns the address raw frames are relative to, the entry of
+// runtime.Callers, which every executable recording stack traces with this
+// package has.
+func rawBase() uintptr {
+	return reflect.ValueOf(runtime.Callers).Pointer()
+}
+
+var buildID struct {
+	once sync.Once
+	id   string
+}
+
+// BuildID returns the Go build ID of the running executable, as printed by
+// "go tool buildid", or the empty string if it cannot be read. Raw frames
+// can only be resolved against an executable with the same build ID.
+func BuildID() string {
+	buildID.once.Do(func() { buildID.id = readBuildID() })
+	return buildID.id
+}
+
+// buildIDPrefix precedes the build ID the linker writes at the start of the
+// text of Go executables other than ELF ones.
+var buildIDPrefix = []byte("\xff Go build ID: \"")
+
+// readBuildID reads the build ID of the running executable, from its
+// .note.go.buildid section for ELF executables and by searching it
+// otherwise.
+func readBuildID() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if e, err := elf.NewFile(f); err == nil {
+		return elfBuildID(e)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return ""
+	}
+	// buf holds the end of the previous chunk followed by the current one,
+	// so that a build ID spanning chunks is found.
+	const overlap = 256
+	buf := make([]byte, overlap+64<<10)
+	n := 0
+	for {
+		m, err := io.ReadFull(f, buf[n:])
+		b := buf[:n+m]
+		for i := bytes.Index(b, buildIDPrefix); i >= 0; {
+			if id, ok := parseBuildID(b[i+len(buildIDPrefix):]); ok {
+				return id
+			}
+			j := bytes.Index(b[i+1:], buildIDPrefix)
+			if j < 0 {
+				break
+			}
+			i += 1 + j
+		}
+		if err != nil || len(b) < overlap {
+			return ""
+		}
+		n = copy(buf, b[len(b)-overlap:])
+	}
+}
+
+// elfBuildID returns the build ID recorded in the Go note of e.
+func elfBuildID(e *elf.File) string {
+	s := e.Section(".note.go.buildid")
+	if s == nil {
+		return ""
+	}
+	b, err := s.Data()
+	if err != nil || len(b) < 16 {
+		return ""
+	}
+	// An ELF note is made of the sizes of its name and description and
+	// its type, followed by the name and description, each padded to 4
+	// bytes.
+	namesz := e.ByteOrder.Uint32(b)
+	descsz := e.ByteOrder.Uint32(b[4:])
+	name := (namesz + 3) &^ 3
+	if namesz < 2 || string(b[12:14]) != "Go" || uint32(len(b)) < 12+name+descsz {
+		return ""
+	}
+	return string(b[12+name : 12+name+descsz])
+}
+
+// parseBuildID returns the build ID b starts with, terminated by a quote.
+// Other occurrences of buildIDPrefix, such as the variable itself, are not
+// followed by a valid build ID.
+func parseBuildID(b []byte) (string, bool) {
+	for i, c := range b {
+		switch {
+		case c == '"':
+			return string(b[:i]), i > 0
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '_', c == '-', c == '/', c == '.', c == '=':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
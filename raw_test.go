@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSetRawFrames(t *testing.T) {
+	defer SetRawFrames(false)
+
+	st, _ := stackTraceOf(New("raw"))
+	SetRawFrames(true)
+	b, err := json.Marshal(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "TestSetRawFrames") {
+		t.Errorf("json.Marshal: got symbolized frames %s", b)
+	}
+	var got StackTrace
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(st) {
+		t.Fatalf("json.Unmarshal: got %d frames, want %d", len(got), len(st))
+	}
+	for i := range st {
+		if got[i] != st[i] {
+			t.Errorf("frame %d: got %#x, want %#x", i, got[i], st[i])
+		}
+	}
+	if name := got[0].Name(); name != "github.com/pkg/errors.TestSetRawFrames" {
+		t.Errorf("frame 0: got %q, want github.com/pkg/errors.TestSetRawFrames", name)
+	}
+
+	var f Frame
+	for _, text := range []string{"", "4a5b1c", "0xzz", "-4a5b1c", "--0x1"} {
+		if err := f.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q): got no error", text)
+		}
+	}
+}
+
+func TestRawFramesOffline(t *testing.T) {
+	defer SetRawFrames(false)
+
+	st, _ := stackTraceOf(New("raw"))
+	SetRawFrames(true)
+	text, err := st[0].MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	out, err := exec.Command("go", "tool", "nm", exe).Output()
+	if err != nil {
+		t.Skip(err) // go test strips the symbols of test binaries without -c
+	}
+	var base uint64
+	for _, line := range strings.Split(string(out), "\n") {
+		if f := strings.Fields(line); len(f) == 3 && f[2] == "runtime.Callers" {
+			base, _ = strconv.ParseUint(f[0], 16, 64)
+		}
+	}
+	if base == 0 {
+		t.Skip("runtime.Callers not found by go tool nm")
+	}
+	s := string(text)
+	var pc uint64
+	if strings.HasPrefix(s, "-0x") {
+		off, _ := strconv.ParseUint(s[3:], 16, 64)
+		pc = base - off
+	} else {
+		off, _ := strconv.ParseUint(s[2:], 16, 64)
+		pc = base + off
+	}
+	cmd := exec.Command("go", "tool", "addr2line", exe)
+	cmd.Stdin = strings.NewReader("0x" + strconv.FormatUint(pc-1, 16) + "\n")
+	out, err = cmd.Output()
+	if err != nil {
+		t.Skip(err)
+	}
+	if got, want := strings.SplitN(string(out), "\n", 2)[0], "github.com/pkg/errors.TestRawFramesOffline"; got != want {
+		t.Errorf("go tool addr2line %s: got %q, want %q", text, got, want)
+	}
+}
+
+func TestBuildID(t *testing.T) {
+	id := BuildID()
+	if id == "" {
+		t.Fatal("BuildID: got an empty build ID")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	out, err := exec.Command("go", "tool", "buildid", exe).Output()
+	if err != nil {
+		t.Skip(err)
+	}
+	if want := strings.TrimSpace(string(out)); id != want {
+		t.Errorf("BuildID: got %q, want %q", id, want)
+	}
+}
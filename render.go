@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// RenderOptions controls the output of Render.
+type RenderOptions struct {
+	// Color enables ANSI colours: the message is printed in bold,
+	// application frames (see Frame.IsApplication) are highlighted and
+	// dependency frames are dimmed. Use ShouldColor to enable colours
+	// only when writing to a terminal.
+	Color bool
+
+	// Order overrides the order the messages of the layers of the chain
+	// are printed in, set with SetFormat, unless it is DefaultOrder.
+	// InnermostFirst prints the root cause first.
+	Order Order
+
+	// Separator overrides the separator printed between the messages of
+	// the layers of the chain, set with SetFormat, unless it is empty.
+	// Command line tools can print each layer on a line of its own with
+	// "\n", for example. The message returned by Error is not affected.
+	Separator string
+}
+
+// palette holds the ANSI escape sequences used to colour rendered errors.
+type palette struct {
+	message     string
+	application string
+	dependency  string
+	reset       string
+}
+
+var ansi = &palette{
+	message:     "\x1b[1m",
+	application: "\x1b[1;36m",
+	dependency:  "\x1b[2m",
+	reset:       "\x1b[0m",
+}
+
+// Render returns a human readable rendering of err meant to be shown to
+// users, for example by command line tools. It prints the same information
+// as %+v, in the layout set with SetFormat: the message of err followed by
+// the stack trace of the outermost error in its chain that has one. Render
+// returns the empty string if err is nil.
+func Render(err error, opts RenderOptions) string {
+	if err == nil {
+		return ""
+	}
+	var p *palette
+	if opts.Color {
+		p = ansi
+	}
+	f := loadConfig().format
+	if opts.Order != DefaultOrder {
+		f.Order = opts.Order
+	}
+	if opts.Separator != "" {
+		f.Separator = opts.Separator
+	}
+	var b strings.Builder
+	if p != nil {
+		b.WriteString(p.message)
+	}
+	b.WriteString(f.message(err))
+	if p != nil {
+		b.WriteString(p.reset)
+	}
+	if !f.HideFields {
+		writeFields(&b, err)
+	}
+	if f.FrameStyle != NoFrames {
+		writeStackOf(&b, err, frameOptions{palette: p})
+	}
+	return b.String()
+}
+
+// ShouldColor reports whether output written to w should be coloured: w
+// must be a terminal, the NO_COLOR environment variable must not be set and
+// TERM must not be "dumb".
+func ShouldColor(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
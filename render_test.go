@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	if got := Render(nil, RenderOptions{}); got != "" {
+		t.Errorf("Render(nil): got %q, want empty", got)
+	}
+
+	err := New("render")
+	if got, want := Render(err, RenderOptions{}), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("Render(err, RenderOptions{}):\n got %q\nwant %q", got, want)
+	}
+
+	defer SetMainModule("")
+	SetMainModule("github.com/pkg/errors")
+	got := Render(err, RenderOptions{Color: true})
+	for _, want := range []string{
+		"\x1b[1mrender\x1b[0m\n",
+		"\n\x1b[1;36mgithub.com/pkg/errors.TestRender\n\t",
+		"\n\x1b[2mtesting.tRunner\n\t",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render(err, RenderOptions{Color: true}): got %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestShouldColor(t *testing.T) {
+	var b strings.Builder
+	if ShouldColor(&b) {
+		t.Errorf("ShouldColor(%T): got true, want false", &b)
+	}
+	f, err := ioutil.TempFile("", "color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if ShouldColor(f) {
+		t.Errorf("ShouldColor(regular file): got true, want false")
+	}
+}
+
+func TestRenderOrder(t *testing.T) {
+	defer SetFormat(FormatConfig{})
+
+	err := Wrap(WithMessage(io.EOF, "read"), "load")
+	tests := []struct {
+		global, order Order
+		want          string
+	}{
+		{DefaultOrder, DefaultOrder, "load: read: EOF\n"},
+		{DefaultOrder, InnermostFirst, "EOF: read: load\n"},
+		{InnermostFirst, DefaultOrder, "EOF: read: load\n"},
+		{InnermostFirst, OutermostFirst, "load: read: EOF\n"},
+	}
+	for i, tt := range tests {
+		SetFormat(FormatConfig{Order: tt.global})
+		if got := Render(err, RenderOptions{Order: tt.order}); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("test %d: got %q, want it to start with %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSeparator(t *testing.T) {
+	defer SetFormat(FormatConfig{})
+
+	err := Wrap(WithMessage(io.EOF, "read"), "load")
+	tests := []struct {
+		global, separator string
+		want              string
+	}{
+		{"", "", "load: read: EOF\n"},
+		{"", " → ", "load → read → EOF\n"},
+		{" | ", "", "load | read | EOF\n"},
+		{" | ", "\n", "load\nread\nEOF\n"},
+	}
+	for i, tt := range tests {
+		SetFormat(FormatConfig{Separator: tt.global})
+		if got := Render(err, RenderOptions{Separator: tt.separator}); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("test %d: got %q, want it to start with %q", i+1, got, tt.want)
+		}
+		if got := err.Error(); got != "load: read: EOF" {
+			t.Errorf("test %d: Error(): got %q, want %q", i+1, got, "load: read: EOF")
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Renderer renders errors for the users of command line tools, who are
+// better served by the chain of messages, one per line, and what they can
+// do about the error than by the output of %+v:
+//
+//	r := errors.Renderer{MaxWidth: 80, ShowHints: true}
+//	fmt.Fprintln(os.Stderr, r.Render(err))
+//
+// prints
+//
+//	load config
+//	  caused by: open /etc/app.yaml: no such file or directory
+//	  hint: create the file or pass -config
+//
+// The zero Renderer prints the messages of the layers of the chain only.
+type Renderer struct {
+	// MaxWidth is the width lines are wrapped at, between words, if
+	// positive. Words longer than the width are not broken.
+	MaxWidth int
+
+	// Indent is printed in front of the lines following the first one,
+	// two spaces if empty.
+	Indent string
+
+	// ShowFields prints the fields of the error; see Fields.
+	ShowFields bool
+
+	// ShowHints prints the hints of the error; see Hints.
+	ShowHints bool
+
+	// FrameLimit is the number of frames of the stack trace of the error
+	// printed. No stack trace is printed if it is 0, and every frame is
+	// if it is negative.
+	FrameLimit int
+
+	// QuietKinds are the kinds of the errors expected in the normal
+	// course of a program, such as invalid user input, whose stack trace
+	// is not printed whatever the frame limit, so that users are not
+	// shown stack traces for their own typos. The kind of an error is
+	// the one returned by KindOf.
+	QuietKinds []Kind
+
+	// Color enables ANSI colours, as for Render.
+	Color bool
+}
+
+// Render returns the rendering of err by r, without a final newline, or
+// the empty string if err is nil.
+func (r *Renderer) Render(err error) string {
+	if err == nil {
+		return ""
+	}
+	indent := r.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	var p *palette
+	style := ""
+	if r.Color {
+		p = ansi
+		style = p.message
+	}
+	var b strings.Builder
+	for i, msg := range Messages(err) {
+		if i == 0 {
+			r.writeWrapped(&b, "", "", msg, style)
+			continue
+		}
+		b.WriteString("\n")
+		r.writeWrapped(&b, indent, "caused by: ", msg, "")
+	}
+	if r.ShowFields {
+		for _, f := range Fields(err) {
+			b.WriteString("\n")
+			r.writeWrapped(&b, indent, f.Key+": ", fmt.Sprint(f.Value), "")
+		}
+	}
+	if r.ShowHints {
+		for _, h := range Hints(err) {
+			b.WriteString("\n")
+			r.writeWrapped(&b, indent, "hint: ", h, "")
+		}
+	}
+	if r.FrameLimit != 0 && !r.quiet(err) {
+		r.writeFrames(&b, err, indent, p)
+	}
+	return b.String()
+}
+
+// quiet reports whether the kind of err is one of the quiet kinds of r.
+func (r *Renderer) quiet(err error) bool {
+	if len(r.QuietKinds) == 0 {
+		return false
+	}
+	kind := KindOf(err)
+	for _, k := range r.QuietKinds {
+		if k == kind && kind != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWrapped writes label followed by text, wrapped at the width of r,
+// with indent in front of each line and the width of label in front of
+// the lines following the first one, so that they line up with it. style,
+// if not empty, is applied to the text.
+func (r *Renderer) writeWrapped(b *strings.Builder, indent, label, text, style string) {
+	hang := indent + strings.Repeat(" ", utf8.RuneCountInString(label))
+	width := 0
+	if r.MaxWidth > 0 {
+		width = r.MaxWidth - utf8.RuneCountInString(hang)
+		if width < 1 {
+			width = 1
+		}
+	}
+	for i, line := range wrapText(text, width) {
+		if i == 0 {
+			b.WriteString(indent)
+			b.WriteString(label)
+		} else {
+			b.WriteString("\n")
+			b.WriteString(hang)
+		}
+		if style != "" {
+			b.WriteString(style)
+			b.WriteString(line)
+			b.WriteString(ansi.reset)
+			continue
+		}
+		b.WriteString(line)
+	}
+}
+
+// writeFrames writes the frames of the stack trace of err, up to the frame
+// limit of r, one per line.
+func (r *Renderer) writeFrames(b *strings.Builder, err error, indent string, p *palette) {
+	st, ok := stackTraceOf(err)
+	if !ok {
+		return
+	}
+	st = st.visible()
+	more := 0
+	if r.FrameLimit > 0 && len(st) > r.FrameLimit {
+		more = len(st) - r.FrameLimit
+		st = st[:r.FrameLimit]
+	}
+	for _, f := range st {
+		b.WriteString("\n")
+		b.WriteString(indent)
+		style := ""
+		if p != nil {
+			style = p.dependency
+			if f.IsApplication() {
+				style = p.application
+			}
+		}
+		b.WriteString(style)
+		b.WriteString("at ")
+		b.WriteString(f.Name())
+		b.WriteString(" (")
+		b.WriteString(f.path())
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(f.Line()))
+		b.WriteString(")")
+		if p != nil {
+			b.WriteString(p.reset)
+		}
+	}
+	if more > 0 {
+		b.WriteString("\n")
+		b.WriteString(indent)
+		b.WriteString("... ")
+		b.WriteString(strconv.Itoa(more))
+		b.WriteString(" more frames")
+	}
+}
+
+// wrapText splits text into lines of at most width runes, breaking it
+// between words; a word longer than width is a line of its own. Newlines
+// in text are kept. If width is not positive, text is only split at its
+// newlines.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		if width <= 0 || utf8.RuneCountInString(para) <= width {
+			lines = append(lines, para)
+			continue
+		}
+		line, n := "", 0
+		for _, word := range strings.Fields(para) {
+			m := utf8.RuneCountInString(word)
+			switch {
+			case n == 0:
+				line, n = word, m
+			case n+1+m <= width:
+				line, n = line+" "+word, n+1+m
+			default:
+				lines = append(lines, line)
+				line, n = word, m
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRenderer(t *testing.T) {
+	var r Renderer
+	if got := r.Render(nil); got != "" {
+		t.Errorf("Render(nil): got %q, want empty", got)
+	}
+
+	err := WithHint(WithFields(Wrap(WithMessage(io.EOF, "read header"), "load config"), Label("file", "app.yaml")), "check the file")
+	tests := []struct {
+		r    Renderer
+		want string
+	}{
+		{Renderer{}, "load config\n  caused by: read header\n  caused by: EOF"},
+		{Renderer{Indent: "\t", ShowFields: true, ShowHints: true},
+			"load config\n\tcaused by: read header\n\tcaused by: EOF\n\tfile: app.yaml\n\thint: check the file"},
+		{Renderer{MaxWidth: 20, ShowHints: true},
+			"load config\n  caused by: read\n             header\n  caused by: EOF\n  hint: check the\n        file"},
+	}
+	for i, tt := range tests {
+		if got := tt.r.Render(err); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	st, _ := stackTraceOf(err)
+	frame := fmt.Sprintf("\n  at %s (%s:%d)", st[0].Name(), st[0].path(), st[0].Line())
+	r = Renderer{FrameLimit: 1}
+	if got, want := r.Render(err), tests[0].want+frame+fmt.Sprintf("\n  ... %d more frames", len(st)-1); got != want {
+		t.Errorf("FrameLimit: 1: got %q, want %q", got, want)
+	}
+	r = Renderer{FrameLimit: -1}
+	if got := r.Render(err); strings.Count(got, "\n  at ") != len(st) || strings.Contains(got, "more frames") {
+		t.Errorf("FrameLimit: -1: got %q, want the %d frames", got, len(st))
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"a b c", 0, []string{"a b c"}},
+		{"a b c", 3, []string{"a b", "c"}},
+		{"looooong a", 4, []string{"looooong", "a"}},
+		{"a\nb c", 5, []string{"a", "b c"}},
+		{"é é é", 3, []string{"é é", "é"}},
+	}
+	for i, tt := range tests {
+		if got := wrapText(tt.text, tt.width); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestRendererQuietKinds(t *testing.T) {
+	const (
+		invalid  Kind = "invalid input"
+		internal Kind = "internal"
+	)
+	r := Renderer{FrameLimit: -1, QuietKinds: []Kind{invalid, "not found"}}
+	tests := []struct {
+		err    error
+		frames bool
+	}{
+		{WithKind(New("bad flag"), invalid), false},
+		{Wrap(WithKind(New("no such user"), "not found"), "lookup"), false},
+		{WithKind(New("bug"), internal), true},
+		{New("bug"), true},
+	}
+	for i, tt := range tests {
+		got := r.Render(tt.err)
+		if frames := strings.Contains(got, "\n  at "); frames != tt.frames {
+			t.Errorf("test %d: got %q, want frames: %t", i+1, got, tt.frames)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// panicWorker panics in a function of its own, so that the crash output of
+// TestRepanicCrash can be checked for its frame.
+func panicWorker() error { return panics("worker") }
+
+func TestRepanicCrash(t *testing.T) {
+	if os.Getenv("ERRORS_TEST_REPANIC") == "1" {
+		Repanic(Wrap(panicWorker(), "shipped"))
+		return
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	cmd := exec.Command(exe, "-test.run=^TestRepanicCrash$")
+	cmd.Env = append(os.Environ(), "ERRORS_TEST_REPANIC=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Repanic: the test binary did not crash:\n%s", out)
+	}
+	got := string(out)
+	if !strings.Contains(got, "panic: shipped: panic: worker\n") {
+		t.Errorf("crash output: got %q, want the message of the error", got)
+	}
+	if !strings.Contains(got, "github.com/pkg/errors.panicWorker\n") {
+		t.Errorf("crash output: got %q, want the stack trace of the worker", got)
+	}
+}
@@ -0,0 +1,37 @@
+package errors
+
+import "fmt"
+
+// Replace returns, if err matches old, as defined by Is, an error matching
+// new that otherwise is err: it has the same message, fields and stack
+// trace. It translates the sentinel errors of a dependency into those of
+// the API of a package at its boundary:
+//
+//	if err := db.Get(key); err != nil {
+//		return errors.Replace(err, sql.ErrNoRows, ErrNotFound)
+//	}
+//
+// The returned error wraps err, so it still matches old. If err does not
+// match old, Replace returns err.
+func Replace(err, old, new error) error {
+	if err == nil || !Is(err, old) {
+		return err
+	}
+	return &withReplacement{err, new}
+}
+
+type withReplacement struct {
+	error
+	replacement error
+}
+
+// Is reports whether the replacement matches target.
+func (w *withReplacement) Is(target error) bool { return Is(w.replacement, target) }
+
+func (w *withReplacement) Cause() error { return w.error }
+
+func (w *withReplacement) Unwrap() error { return w.error }
+
+func (w *withReplacement) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withReplacement) own() {}
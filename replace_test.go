@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	errNotFound := New("not found")
+	inner := WithFields(os.ErrNotExist, Label("key", "a"))
+	err := Replace(Wrap(inner, "get"), os.ErrNotExist, errNotFound)
+	if got, want := err.Error(), "get: file does not exist"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if !Is(err, errNotFound) || !Is(err, os.ErrNotExist) {
+		t.Errorf("Replace: %v does not match both the replacement and the original", err)
+	}
+	if fields := Fields(err); len(fields) != 1 || fields[0] != Label("key", "a") {
+		t.Errorf("Fields: got %v", fields)
+	}
+	if st, ok := stackTraceOf(err); !ok || st[0].Name() != "github.com/pkg/errors.TestReplace" {
+		t.Errorf("Replace: got stack trace %v", st)
+	}
+	if got := fmt.Sprintf("%+v", err); got != fmt.Sprintf("%+v", Unwrap(err)) {
+		t.Errorf("%%+v: got %q", got)
+	}
+	if Depth(err) != 2 {
+		t.Errorf("Depth: got %d, want 2", Depth(err))
+	}
+
+	plain := Wrap(io.EOF, "read")
+	if got := Replace(plain, os.ErrNotExist, errNotFound); got != plain {
+		t.Errorf("Replace: got %v for an error not matching", got)
+	}
+	if Replace(nil, nil, errNotFound) != nil {
+		t.Errorf("Replace(nil): got non-nil error")
+	}
+}
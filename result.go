@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+// Result holds either a value or the error that prevented computing it,
+// for code passing outcomes around as values, such as pipelines connected
+// by channels:
+//
+//	results := make(chan errors.Result[Item])
+//	...
+//	results <- errors.ResultOf(fetch(id))
+//	...
+//	item, err := (<-results).Unwrap()
+//
+// The zero Result holds the zero value of T and no error.
+type Result[T any] struct {
+	v   T
+	err error
+}
+
+// Ok returns a Result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{v: v}
+}
+
+// Err returns a Result holding err, annotated with the stack trace of the
+// point Err was called if it has none, as by EnsureStack. If err is nil,
+// Err returns the zero Result.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: ensureStack(err)}
+}
+
+// ResultOf returns a Result holding err, as Err does, if it is not nil, and
+// holding v otherwise.
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: ensureStack(err)}
+	}
+	return Result[T]{v: v}
+}
+
+// Unwrap returns the value and the error held by r.
+func (r Result[T]) Unwrap() (T, error) {
+	return r.v, r.err
+}
+
+// Err returns the error held by r, or nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// IsOk reports whether r holds a value rather than an error.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// OrElse returns the value held by r, or v if r holds an error.
+func (r Result[T]) OrElse(v T) T {
+	if r.err != nil {
+		return v
+	}
+	return r.v
+}
+
+// MapErr returns r with its error replaced by fn(err), such as a wrapped
+// err, if it holds one, and r otherwise. If fn returns nil, MapErr returns
+// the zero Result.
+func (r Result[T]) MapErr(fn func(err error) error) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	return Result[T]{err: fn(r.err)}
+}
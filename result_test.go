@@ -0,0 +1,45 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import (
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	ok := Ok(42)
+	if v, err := ok.Unwrap(); v != 42 || err != nil {
+		t.Errorf("Ok: got %v, %v, want 42, nil", v, err)
+	}
+	if !ok.IsOk() || ok.Err() != nil || ok.OrElse(0) != 42 {
+		t.Errorf("Ok: got IsOk %v, Err %v, OrElse %v", ok.IsOk(), ok.Err(), ok.OrElse(0))
+	}
+	if ok.MapErr(func(error) error { return io.EOF }).Err() != nil {
+		t.Errorf("Ok: MapErr changed the result")
+	}
+
+	r := Err[int](io.EOF)
+	if r.IsOk() || !Is(r.Err(), io.EOF) || r.OrElse(7) != 7 {
+		t.Errorf("Err: got IsOk %v, Err %v, OrElse %v", r.IsOk(), r.Err(), r.OrElse(7))
+	}
+	if st, _ := stackTraceOf(r.Err()); len(st) == 0 || st[0].Name() != "github.com/pkg/errors.TestResult" {
+		t.Errorf("Err: got stack trace %v, want one starting in TestResult", st)
+	}
+	mapped := r.MapErr(func(err error) error { return Wrap(err, "mapped") })
+	if got, want := mapped.Err().Error(), "mapped: EOF"; got != want {
+		t.Errorf("MapErr: got %q, want %q", got, want)
+	}
+	if Err[int](nil).Err() != nil {
+		t.Errorf("Err(nil): got an error")
+	}
+
+	if v, err := ResultOf(strconv.Atoi("12")).Unwrap(); v != 12 || err != nil {
+		t.Errorf("ResultOf: got %v, %v, want 12, nil", v, err)
+	}
+	if err := ResultOf(strconv.Atoi("x")).Err(); err == nil || !HasStack(err) {
+		t.Errorf("ResultOf: got %v, want an error with a stack trace", err)
+	}
+}
@@ -0,0 +1,209 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Retryable annotates err as retryable, reported by IsRetryable, without
+// changing its message. If err is nil, Retryable returns nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetry{error: err, retryable: true}
+}
+
+// RetryableAfter annotates err as retryable once d has elapsed, reported by
+// IsRetryable and RetryAfter, without changing its message. If err is nil,
+// RetryableAfter returns nil.
+func RetryableAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &withRetry{error: err, retryable: true, after: d, hasAfter: true}
+}
+
+// IsRetryable reports whether the first error in err's chain annotated by
+// Retryable or RetryableAfter, or having a Retryable() bool method, is
+// retryable.
+func IsRetryable(err error) bool {
+	var retryable bool
+	find(err, func(err error) bool {
+		e, ok := err.(interface{ Retryable() bool })
+		if ok {
+			retryable = e.Retryable()
+		}
+		return ok
+	})
+	return retryable
+}
+
+// RetryAfter returns the delay after which err can be retried, as set by
+// RetryableAfter on the first error in err's chain having one, and reports
+// whether there is one.
+func RetryAfter(err error) (time.Duration, bool) {
+	var after time.Duration
+	ok := find(err, func(err error) bool {
+		w, ok := err.(*withRetry)
+		if ok && w.hasAfter {
+			after = w.after
+			return true
+		}
+		return false
+	})
+	return after, ok
+}
+
+type withRetry struct {
+	error
+	retryable bool
+	after     time.Duration
+	hasAfter  bool
+}
+
+func (w *withRetry) Retryable() bool { return w.retryable }
+
+func (w *withRetry) Cause() error { return w.error }
+
+func (w *withRetry) Unwrap() error { return w.error }
+
+func (w *withRetry) Format(s fmt.State, verb rune) { formatError(s, verb, w) }
+
+func (*withRetry) own() {}
+
+// RetryPolicy controls how Retry retries. The zero RetryPolicy makes three
+// attempts, waiting 100ms then 200ms between them, and retries the errors
+// reported retryable by IsRetryable.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, 3 if zero.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt, 100ms if zero.
+	// Each following delay is Multiplier times the previous one, up to
+	// MaxBackoff, unless the error sets its own with RetryableAfter.
+	Backoff    time.Duration
+	Multiplier float64 // 2 if zero
+	MaxBackoff time.Duration
+
+	// Retryable reports whether an error is retried, IsRetryable if nil.
+	Retryable func(err error) bool
+
+	// History is the number of failed attempts, before the last, kept in
+	// the error returned by Retry, 10 if zero.
+	History int
+}
+
+// Attempt is an attempt made by Retry.
+type Attempt struct {
+	Err      error
+	Start    time.Time
+	Duration time.Duration
+}
+
+// RetryError is the error returned by Retry when all the attempts failed.
+// Its message is that of the last attempt, prefixed with the number of
+// attempts made, and %+v also prints the previous attempts.
+type RetryError struct {
+	// Last is the error of the last attempt, which is the cause of the
+	// RetryError.
+	Last error
+
+	// Attempts is the number of attempts made.
+	Attempts int
+
+	// History holds the most recent failed attempts before the last,
+	// oldest first, up to RetryPolicy.History of them.
+	History []Attempt
+
+	// Interrupted is the error of the context if it was done before all
+	// the attempts were made, and nil otherwise.
+	Interrupted error
+}
+
+func (e *RetryError) Error() string {
+	if e.Interrupted != nil {
+		return fmt.Sprintf("retry interrupted (%v) after %d attempts: %v", e.Interrupted, e.Attempts, e.Last)
+	}
+	return fmt.Sprintf("after %d attempts: %v", e.Attempts, e.Last)
+}
+
+func (e *RetryError) Cause() error { return e.Last }
+
+func (e *RetryError) Unwrap() error { return e.Last }
+
+func (*RetryError) own() {}
+
+func (e *RetryError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, e)
+	if verb == 'v' && s.Flag('+') {
+		for _, a := range e.History {
+			fmt.Fprintf(s, "\nprevious attempt at %s, after %s: %v", a.Start.Format(time.RFC3339Nano), a.Duration, a.Err)
+		}
+	}
+}
+
+// Retry calls fn until it succeeds, returns an error that is not
+// retryable, or the attempts allowed by policy are exhausted, waiting
+// between attempts as set by policy and by RetryableAfter. Retry returns
+// nil if fn succeeded, the error of fn if it is not retryable, and a
+// *RetryError otherwise, including if ctx is done before an attempt.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	p := policy.withDefaults()
+	var history []Attempt
+	delay := p.Backoff
+	for n := 1; ; n++ {
+		start := time.Now()
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		attempt := Attempt{err, start, time.Since(start)}
+		if !p.Retryable(err) {
+			return err
+		}
+		if n == p.MaxAttempts {
+			return &RetryError{Last: err, Attempts: n, History: history}
+		}
+		wait := delay
+		if d, ok := RetryAfter(err); ok {
+			wait = d
+		}
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			delay = p.MaxBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &RetryError{Last: err, Attempts: n, History: history, Interrupted: ctx.Err()}
+		case <-timer.C:
+		}
+		history = append(history, attempt)
+		if len(history) > p.History {
+			history = history[1:]
+		}
+	}
+}
+
+// withDefaults returns p with its zero fields set to their defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = 100 * time.Millisecond
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+	if p.History <= 0 {
+		p.History = 10
+	}
+	return p
+}
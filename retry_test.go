@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	if IsRetryable(io.EOF) || IsRetryable(nil) {
+		t.Errorf("IsRetryable: got true for an error not annotated")
+	}
+	err := Wrap(Retryable(io.EOF), "wrapped")
+	if !IsRetryable(err) || err.Error() != "wrapped: EOF" {
+		t.Errorf("Retryable: got %v, IsRetryable %v", err, IsRetryable(err))
+	}
+	if _, ok := RetryAfter(err); ok {
+		t.Errorf("RetryAfter(%v): got a delay", err)
+	}
+	err = RetryableAfter(io.EOF, time.Second)
+	if d, ok := RetryAfter(err); !ok || d != time.Second || !IsRetryable(err) {
+		t.Errorf("RetryAfter(%v): got %v, %v", err, d, ok)
+	}
+	if Retryable(nil) != nil || RetryableAfter(nil, time.Second) != nil {
+		t.Errorf("Retryable(nil): got non-nil error")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, Backoff: time.Millisecond, History: 2}
+	n := 0
+	err := Retry(context.Background(), policy, func(context.Context) error {
+		n++
+		if n < 3 {
+			return Retryable(fmt.Errorf("attempt %d", n))
+		}
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Errorf("Retry: got %v after %d attempts, want nil after 3", err, n)
+	}
+
+	n = 0
+	err = Retry(context.Background(), policy, func(context.Context) error {
+		n++
+		return Retryable(fmt.Errorf("attempt %d", n))
+	})
+	var re *RetryError
+	if !As(err, &re) {
+		t.Fatalf("Retry: got %v, want a *RetryError", err)
+	}
+	if got, want := err.Error(), "after 4 attempts: attempt 4"; got != want {
+		t.Errorf("Error(): got %q, want %q", got, want)
+	}
+	if len(re.History) != 2 || re.History[0].Err.Error() != "attempt 2" || re.History[1].Err.Error() != "attempt 3" {
+		t.Errorf("History: got %v, want attempts 2 and 3", re.History)
+	}
+	if out := fmt.Sprintf("%+v", err); !strings.Contains(out, ": attempt 3") {
+		t.Errorf("%%+v: got %q, want the previous attempts", out)
+	}
+
+	n = 0
+	err = Retry(context.Background(), policy, func(context.Context) error {
+		n++
+		return io.EOF
+	})
+	if err != io.EOF || n != 1 {
+		t.Errorf("Retry: got %v after %d attempts, want io.EOF after 1", err, n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = Retry(ctx, RetryPolicy{Backoff: time.Hour}, func(context.Context) error {
+		cancel()
+		return Retryable(io.EOF)
+	})
+	if !As(err, &re) || re.Interrupted != context.Canceled || re.Attempts != 1 || !Is(err, io.EOF) {
+		t.Errorf("Retry: got %v, want an interrupted *RetryError", err)
+	}
+}
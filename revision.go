@@ -0,0 +1,17 @@
+//go:build go1.18
+// +build go1.18
+
+package errors
+
+import "runtime/debug"
+
+// vcsRevision returns the revision of the main module recorded by the
+// go command in bi when it was built from a version control checkout.
+func vcsRevision(bi *debug.BuildInfo) string {
+	for _, s := range bi.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return ""
+}
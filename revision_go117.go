@@ -0,0 +1,10 @@
+//go:build !go1.18
+// +build !go1.18
+
+package errors
+
+import "runtime/debug"
+
+// vcsRevision returns the empty string: the go command only records the
+// revision of the main module since Go 1.18.
+func vcsRevision(bi *debug.BuildInfo) string { return "" }
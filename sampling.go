@@ -0,0 +1,21 @@
+package errors
+
+import "math/rand"
+
+// SetStackSampling makes the functions that record a stack trace capture
+// it for only the given fraction of the errors they create: 0.01 captures
+// the stack trace of one error in a hundred on average. The other errors
+// record an empty stack trace, printed by %+v as "(stack trace not
+// sampled)", so that wrapping them does not capture a stack trace either.
+// A rate of 1 or more, the default, captures every stack trace.
+func SetStackSampling(rate float64) {
+	updateConfig(func(c *config) {
+		c.sampled = rate < 1
+		c.sampling = rate
+	})
+}
+
+// sampled reports whether the stack trace being captured with c is sampled.
+func sampled(c *config) bool {
+	return !c.sampled || rand.Float64() < c.sampling
+}
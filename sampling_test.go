@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetStackSampling(t *testing.T) {
+	defer SetStackSampling(1)
+
+	SetStackSampling(0)
+	err := New("unsampled")
+	if got, want := fmt.Sprintf("%+v", err), "unsampled\n(stack trace not sampled)"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+	if st, ok := stackTraceOf(err); !ok || len(st) != 0 {
+		t.Errorf("StackTrace(): got %v, %v, want an empty stack trace", st, ok)
+	}
+	wrapped := Wrap(err, "wrapped")
+	if got, want := fmt.Sprintf("%+v", wrapped), "wrapped: unsampled\n(stack trace not sampled)"; got != want {
+		t.Errorf("%%+v: got %q, want %q", got, want)
+	}
+
+	SetStackSampling(0.5)
+	n := 0
+	for i := 0; i < 1000; i++ {
+		if st, _ := stackTraceOf(Wrap(io.EOF, "sampled")); len(st) > 0 {
+			n++
+		}
+	}
+	if n < 350 || n > 650 {
+		t.Errorf("SetStackSampling(0.5): %d of 1000 stack traces sampled", n)
+	}
+
+	SetStackSampling(1)
+	if got := fmt.Sprintf("%+v", New("sampled")); strings.Contains(got, "not sampled") {
+		t.Errorf("%%+v: got %q, want a stack trace", got)
+	}
+}
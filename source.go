@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SetSourceContext enables printing n lines of source code before and
+// after the line of every application frame (see Frame.IsApplication) when
+// a stack trace is formatted with %+v. The source is read from the file
+// recorded by the compiler, so it is only shown for binaries running where
+// they were built; frames whose source cannot be read are printed as
+// usual. SetSourceContext is meant for development; n <= 0, the default,
+// disables it.
+func SetSourceContext(n int) {
+	updateConfig(func(c *config) { c.sourceContext = nonNegative(n) })
+}
+
+var sourceCache struct {
+	sync.Mutex
+	files map[string][]string // nil if the file could not be read
+}
+
+// sourceLines returns the lines of file, or nil if it cannot be read.
+func sourceLines(file string) []string {
+	sourceCache.Lock()
+	defer sourceCache.Unlock()
+	if lines, ok := sourceCache.files[file]; ok {
+		return lines
+	}
+	var lines []string
+	if b, err := ioutil.ReadFile(file); err == nil {
+		lines = strings.Split(string(bytes.TrimRight(b, "\n")), "\n")
+	}
+	if sourceCache.files == nil {
+		sourceCache.files = make(map[string][]string)
+	}
+	sourceCache.files[file] = lines
+	return lines
+}
+
+// writeSource writes the n lines of source around the line of f, one per
+// line, each preceded by a newline. The line of f is marked with '>'.
+func writeSource(w io.Writer, f Frame, n int) {
+	lines := sourceLines(f.File())
+	line := f.Line()
+	if line < 1 || line > len(lines) {
+		return
+	}
+	first, last := line-n, line+n
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+	for i := first; i <= last; i++ {
+		mark := " "
+		if i == line {
+			mark = ">"
+		}
+		fmt.Fprintf(w, "\n\t%s %*d | %s", mark, width, i, lines[i-1])
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSetSourceContext(t *testing.T) {
+	defer SetMainModule("")
+	defer SetSourceContext(0)
+
+	SetMainModule("github.com/pkg/errors")
+	SetSourceContext(1)
+	err := New("with source") // the marked line
+	got := fmt.Sprintf("%+v", err)
+	lines := strings.Split(got, "\n")
+	if len(lines) < 6 {
+		t.Fatalf("%%+v: got %q, want source lines", got)
+	}
+	if !strings.HasSuffix(lines[2], "source_test.go:15") {
+		t.Fatalf("%%+v: line 3: got %q, want the frame of the caller", lines[2])
+	}
+	want := []string{
+		"\t  14 | \tSetSourceContext(1)",
+		"\t> 15 | \terr := New(\"with source\") // the marked line",
+		"\t  16 | \tgot := fmt.Sprintf(\"%+v\", err)",
+	}
+	for i, w := range want {
+		if lines[3+i] != w {
+			t.Errorf("%%+v: line %d: got %q, want %q", 4+i, lines[3+i], w)
+		}
+	}
+	if n := strings.Count(got, " | "); n != len(want) {
+		t.Errorf("%%+v: got %d source lines, want %d: source is only printed for application frames", n, len(want))
+	}
+
+	SetSourceContext(0)
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "| ") {
+		t.Errorf("%%+v without source context: got %q", got)
+	}
+}
+
+func TestWriteSourceUnknown(t *testing.T) {
+	var b strings.Builder
+	writeSource(&b, 0, 2)
+	if b.Len() != 0 {
+		t.Errorf("writeSource(unknown frame): got %q, want nothing", b.String())
+	}
+}
@@ -2,11 +2,13 @@ package errors
 
 import (
 	"fmt"
+	"hash/fnv"
 	"io"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // Frame represents a program counter inside a stack frame.
@@ -14,6 +16,10 @@ import (
 // its value represents the program counter + 1.
 type Frame uintptr
 
+// FrameFromPC returns the Frame of a program counter as reported by
+// runtime.Callers, that is the return address of a call.
+func FrameFromPC(pc uintptr) Frame { return Frame(pc) }
+
 // PC returns the program counter for this frame;
 // multiple frames may have the same PC value.
 func (f Frame) PC() uintptr { return uintptr(f) - 1 }
@@ -21,32 +27,18 @@ func (f Frame) PC() uintptr { return uintptr(f) - 1 }
 // File returns the full path to the file that contains the
 // function for this Frame's pc.
 func (f Frame) File() string {
-	fn := runtime.FuncForPC(f.PC())
-	if fn == nil {
-		return "unknown"
-	}
-	file, _ := fn.FileLine(f.PC())
-	return file
+	return f.symbol().file
 }
 
 // Line returns the line number of source code of the
 // function for this Frame's pc.
 func (f Frame) Line() int {
-	fn := runtime.FuncForPC(f.PC())
-	if fn == nil {
-		return 0
-	}
-	_, line := fn.FileLine(f.PC())
-	return line
+	return f.symbol().line
 }
 
 // Name returns the name of this function, if known.
 func (f Frame) Name() string {
-	fn := runtime.FuncForPC(f.PC())
-	if fn == nil {
-		return "unknown"
-	}
-	return fn.Name()
+	return f.symbol().name
 }
 
 // Format formats the frame according to the fmt.Formatter interface.
@@ -59,7 +51,10 @@ func (f Frame) Name() string {
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
 //    %+s   function name and path of source file relative to the compile time
-//          GOPATH separated by \n\t (<funcname>\n\t<path>)
+//          GOPATH separated by \n\t (<funcname>\n\t<path>); the path is
+//          rendered according to the options set with SetPathOptions
+//    %+n   function name including its import path and receiver, such as
+//          github.com/pkg/errors.(*X).ptr
 //    %+v   equivalent to %+s:%d
 func (f Frame) Format(s fmt.State, verb rune) {
 	switch verb {
@@ -68,14 +63,19 @@ func (f Frame) Format(s fmt.State, verb rune) {
 		case s.Flag('+'):
 			io.WriteString(s, f.Name())
 			io.WriteString(s, "\n\t")
-			io.WriteString(s, f.File())
+			io.WriteString(s, f.path())
 		default:
-			io.WriteString(s, path.Base(f.File()))
+			io.WriteString(s, path.Base(f.path()))
 		}
 	case 'd':
 		io.WriteString(s, strconv.Itoa(f.Line()))
 	case 'n':
-		io.WriteString(s, funcname(f.Name()))
+		switch {
+		case s.Flag('+'):
+			io.WriteString(s, f.Name())
+		default:
+			io.WriteString(s, funcname(f.Name()))
+		}
 	case 'v':
 		f.Format(s, 's')
 		io.WriteString(s, ":")
@@ -84,18 +84,51 @@ func (f Frame) Format(s fmt.State, verb rune) {
 }
 
 // MarshalText formats a stacktrace Frame as a text string. The output is the
-// same as that of fmt.Sprintf("%+v", f), but without newlines or tabs.
+// same as that of fmt.Sprintf("%+v", f), but without newlines or tabs,
+// unless raw frames are enabled, see SetRawFrames, or the frame is printed
+// symbolically, see SetSymbolicFrames.
 func (f Frame) MarshalText() ([]byte, error) {
+	c := loadConfig()
+	if c.rawFrames {
+		return f.rawText(), nil
+	}
+	if f.symbolic(c) {
+		return []byte(f.Symbolic()), nil
+	}
 	name := f.Name()
 	if name == "unknown" {
 		return []byte(name), nil
 	}
-	return []byte(fmt.Sprintf("%s %s:%d", name, f.File(), f.Line())), nil
+	return []byte(fmt.Sprintf("%s %s:%d", name, f.path(), f.Line())), nil
+}
+
+// Key returns a string identifying the call site of this Frame by function
+// name, source file name and line, such as
+// "github.com/pkg/errors.New errors.go:102". Unlike the program counter,
+// the key does not change between runs or rebuilds of the same source, nor
+// with the directory the source was built in, and can be used to group
+// frames, for example to deduplicate reports.
+func (f Frame) Key() string {
+	name := f.Name()
+	if name == "unknown" {
+		return name
+	}
+	return name + " " + path.Base(normalizePath(f.File())) + ":" + strconv.Itoa(f.Line())
 }
 
 // StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
 type StackTrace []Frame
 
+// StackTraceFromPCs returns the StackTrace of program counters as reported
+// by runtime.Callers, innermost first.
+func StackTraceFromPCs(pcs []uintptr) StackTrace {
+	st := make(StackTrace, len(pcs))
+	for i, pc := range pcs {
+		st[i] = FrameFromPC(pc)
+	}
+	return st
+}
+
 // Format formats the stack of Frames according to the fmt.Formatter interface.
 //
 //    %s	lists source files for each Frame in the stack
@@ -104,15 +137,14 @@ type StackTrace []Frame
 // Format accepts flags that alter the printing of some verbs, as follows:
 //
 //    %+v   Prints filename, function, and line number for each Frame in the stack.
+//    %#v   Prints the stack as a Go slice literal of Frames, each printed as
+//          %v, such as []errors.Frame{stack.go:12, errors.go:102}.
 func (st StackTrace) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		switch {
 		case s.Flag('+'):
-			for _, f := range st {
-				io.WriteString(s, "\n")
-				f.Format(s, verb)
-			}
+			st.visible().writeFrames(s, frameOptions{})
 		case s.Flag('#'):
 			fmt.Fprintf(s, "%#v", []Frame(st))
 		default:
@@ -126,46 +158,232 @@ func (st StackTrace) Format(s fmt.State, verb rune) {
 // formatSlice will format this StackTrace into the given buffer as a slice of
 // Frame, only valid when called with '%s' or '%v'.
 func (st StackTrace) formatSlice(s fmt.State, verb rune) {
+	custom := loadConfig().frameFormatter
 	io.WriteString(s, "[")
-	for i, f := range st {
+	for i, f := range st.visible() {
 		if i > 0 {
 			io.WriteString(s, " ")
 		}
+		if custom != nil && verb == 'v' {
+			custom(s, f, false)
+			continue
+		}
 		f.Format(s, verb)
 	}
 	io.WriteString(s, "]")
 }
 
+// Hash returns a hash of the keys of the frames of st; see Frame.Key.
+// Stack traces with the same call sites have the same hash.
+func (st StackTrace) Hash() uint64 {
+	h := fnv.New64a()
+	for _, f := range st {
+		io.WriteString(h, f.Key())
+		io.WriteString(h, "\n")
+	}
+	return h.Sum64()
+}
+
+// TrimBelow returns st without the outermost frames it has in common with
+// other. It is typically used to strip the frames of a well known caller,
+// such as the request serving loop of a server, from the stack trace of an
+// error:
+//
+//	st = st.TrimBelow(serverStack)
+func (st StackTrace) TrimBelow(other StackTrace) StackTrace {
+	return st[:len(st)-commonSuffix(st, other)]
+}
+
+// TrimCommonSuffix returns a and b without the outermost frames they have
+// in common.
+func TrimCommonSuffix(a, b StackTrace) (StackTrace, StackTrace) {
+	n := commonSuffix(a, b)
+	return a[:len(a)-n], b[:len(b)-n]
+}
+
+// commonSuffix returns the number of outermost frames a and b have in
+// common.
+func commonSuffix(a, b StackTrace) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// frameOptions alters how writeFrames prints frames.
+type frameOptions struct {
+	// palette, if not nil, is used to colour frames.
+	palette *palette
+
+	// limit overrides the limit set with SetStackLimit if positive.
+	limit int
+}
+
+// writeFrames writes each Frame of st in the %+v layout, each preceded by a
+// newline.
+func (st StackTrace) writeFrames(w io.Writer, o frameOptions) {
+	c := loadConfig()
+	limit := o.limit
+	if limit <= 0 {
+		limit = c.stackLimit
+	}
+	if limit > 0 && len(st) > 2*limit {
+		st[:limit].writeFrames(w, frameOptions{palette: o.palette, limit: limit})
+		writeElided(w, len(st)-2*limit)
+		st = st[len(st)-limit:]
+	}
+	for _, f := range st {
+		writeFrame(w, f, c, o.palette)
+	}
+}
+
+// writeFrame writes f in the %+v layout preceded by a newline.
+func writeFrame(w io.Writer, f Frame, c *config, p *palette) {
+	io.WriteString(w, "\n")
+	app := (p != nil || c.appMarker != "" || c.sourceContext > 0) && f.IsApplication()
+	if app && c.appMarker != "" {
+		io.WriteString(w, c.appMarker)
+	}
+	style := ""
+	if p != nil {
+		style = p.dependency
+		if app {
+			style = p.application
+		}
+	}
+	io.WriteString(w, style)
+	if c.frameFormatter != nil {
+		c.frameFormatter(w, f, true)
+	} else if f.symbolic(c) {
+		io.WriteString(w, f.Symbolic())
+	} else if c.format.FrameStyle == OneLineFrames {
+		io.WriteString(w, f.Name())
+		io.WriteString(w, " ")
+		io.WriteString(w, f.path())
+		io.WriteString(w, ":")
+		io.WriteString(w, strconv.Itoa(f.Line()))
+	} else {
+		io.WriteString(w, f.Name())
+		io.WriteString(w, "\n\t")
+		io.WriteString(w, f.path())
+		io.WriteString(w, ":")
+		io.WriteString(w, strconv.Itoa(f.Line()))
+	}
+	if p != nil {
+		io.WriteString(w, p.reset)
+	}
+	if app && c.sourceContext > 0 {
+		writeSource(w, f, c.sourceContext)
+	}
+}
+
+// stackTraceOf returns the StackTrace of the first error in err's chain
+// that has one.
+func stackTraceOf(err error) (StackTrace, bool) {
+	st, ok := firstStackTracer(err)
+	if !ok {
+		return nil, false
+	}
+	return st.StackTrace(), true
+}
+
 // stack represents a stack of program counters.
 type stack []uintptr
 
-func (s *stack) Format(st fmt.State, verb rune) {
+func (s stack) Format(st fmt.State, verb rune) {
 	switch verb {
 	case 'v':
 		switch {
 		case st.Flag('+'):
-			for _, pc := range *s {
-				f := Frame(pc)
-				fmt.Fprintf(st, "\n%+v", f)
-			}
+			s.StackTrace().visible().writeFrames(st, frameOptions{})
 		}
 	}
 }
 
-func (s *stack) StackTrace() StackTrace {
-	f := make([]Frame, len(*s))
+func (s stack) StackTrace() StackTrace {
+	f := make([]Frame, len(s))
 	for i := 0; i < len(f); i++ {
-		f[i] = Frame((*s)[i])
+		f[i] = Frame(s[i])
 	}
 	return f
 }
 
-func callers(i int) *stack {
-	const depth = 32
-	var pcs [depth]uintptr
+// SetStackCapture enables or disables capturing stack traces in New,
+// Errorf, Wrap and the other functions documented to record one. While
+// disabled they return errors without a stack trace, as NewNoStack and
+// WrapNoStack do, which are much cheaper to create. SetStackCapture is safe
+// to call at any time, for example to shed load during an incident; stack
+// capture is enabled by default.
+//
+// Building with the noerrstack build tag compiles stack capture out
+// entirely, for targets where binary size or the cost of capture matter:
+// the functions of the package then never record stack traces, whatever
+// SetStackCapture is called with.
+func SetStackCapture(enabled bool) {
+	updateConfig(func(c *config) { c.noStackCapture = !enabled })
+}
+
+// captureEnabled reports whether stack traces should be captured.
+func captureEnabled() bool {
+	return capturing(loadConfig())
+}
+
+// capturing reports whether stack traces should be captured with c.
+func capturing(c *config) bool {
+	return stackCapture && !c.noStackCapture
+}
+
+// callersPool holds the buffers callers collects program counters in.
+var callersPool = sync.Pool{
+	New: func() interface{} { return new([maxDepth]uintptr) },
+}
+
+// maxDepth is the maximum number of frames recorded in a stack trace.
+const maxDepth = 32
+
+// SetSingleFrameCapture makes the functions that record a stack trace
+// record only the frame of their caller rather than the whole stack, which
+// is several times cheaper to capture. Stack traces then locate where
+// errors were created and wrapped but not how that code was reached.
+func SetSingleFrameCapture(enabled bool) {
+	updateConfig(func(c *config) { c.singleFrame = enabled })
+}
+
+// callers returns the stack of the caller of the caller of callers,
+// skipping i more frames. Program counters are collected in a pooled
+// buffer, so that only the returned stack is allocated. callers returns a
+// nil stack if the stack trace is not sampled.
+func callers(i int) stack {
+	return callersWith(loadConfig(), i+1)
+}
+
+// callersWith is like callers, capturing the stack as configured by c.
+func callersWith(c *config, i int) stack {
+	if !sampled(c) {
+		return nil
+	}
+	if c.singleFrame {
+		st := make(stack, 1)
+		return st[:runtime.Callers(3+i, st)]
+	}
+	pcs := callersPool.Get().(*[maxDepth]uintptr)
 	n := runtime.Callers(3+i, pcs[:])
-	var st stack = pcs[0:n]
-	return &st
+	st := make(stack, n)
+	copy(st, pcs[:n])
+	callersPool.Put(pcs)
+	return st
+}
+
+// pkgname returns the import path of the package a function's name reported
+// by func.Name() belongs to.
+func pkgname(name string) string {
+	i := strings.LastIndex(name, "/")
+	j := strings.Index(name[i+1:], ".")
+	if j < 0 {
+		return name
+	}
+	return name[:i+1+j]
 }
 
 // funcname removes the path prefix component of a function's name reported by func.Name().
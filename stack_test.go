@@ -248,3 +248,99 @@ func caller() Frame {
 	frame, _ := frames.Next()
 	return Frame(frame.PC)
 }
+
+func TestStackTraceTrimBelow(t *testing.T) {
+	outer := func(f func() StackTrace) StackTrace { return f() }
+	base := stackTrace()
+	a := outer(func() StackTrace { return stackTrace() })
+	b := outer(stackTrace)
+
+	got := a.TrimBelow(base)
+	if len(got) == 0 || len(got) >= len(a) {
+		t.Fatalf("TrimBelow: got %d frames of %d", len(got), len(a))
+	}
+	if got[0] != a[0] {
+		t.Errorf("TrimBelow: innermost frame %v, want %v", got[0], a[0])
+	}
+	if n := len(a) - len(got); a[len(a)-n] != base[len(base)-n] {
+		t.Errorf("TrimBelow: trimmed frames are not common to both stacks")
+	}
+	if got := a.TrimBelow(nil); len(got) != len(a) {
+		t.Errorf("TrimBelow(nil): got %d frames, want %d", len(got), len(a))
+	}
+	if got := a.TrimBelow(a); len(got) != 0 {
+		t.Errorf("TrimBelow(itself): got %v, want no frames", got)
+	}
+
+	ta, tb := TrimCommonSuffix(a, b)
+	if len(ta) == 0 || len(tb) == 0 {
+		t.Fatalf("TrimCommonSuffix: got %v and %v, want non empty stacks", ta, tb)
+	}
+	if ta[len(ta)-1] == tb[len(tb)-1] {
+		t.Errorf("TrimCommonSuffix: outermost frames %v and %v are still common", ta[len(ta)-1], tb[len(tb)-1])
+	}
+	if len(a)-len(ta) != len(b)-len(tb) {
+		t.Errorf("TrimCommonSuffix: trimmed %d and %d frames, want the same count", len(a)-len(ta), len(b)-len(tb))
+	}
+}
+
+func TestFrameKey(t *testing.T) {
+	want := fmt.Sprintf("github.com/pkg/errors.init stack_test.go:%d", initpc.Line())
+	if got := initpc.Key(); got != want {
+		t.Errorf("Key(): got %q, want %q", got, want)
+	}
+	if got := Frame(0).Key(); got != "unknown" {
+		t.Errorf("Key() of unknown frame: got %q, want %q", got, "unknown")
+	}
+}
+
+func TestStackTraceHash(t *testing.T) {
+	sts := make([]StackTrace, 2)
+	for i := range sts {
+		sts[i] = stackTrace()
+	}
+	if sts[0].Hash() != sts[1].Hash() {
+		t.Errorf("Hash(): stacks %v and %v from the same call site hash differently", sts[0], sts[1])
+	}
+	if other := stackTrace(); other.Hash() == sts[0].Hash() {
+		t.Errorf("Hash(): stacks %v and %v from different call sites hash the same", other, sts[0])
+	}
+	if StackTrace(nil).Hash() == sts[0].Hash() {
+		t.Errorf("Hash(): empty stack hashes like %v", sts[0])
+	}
+}
+
+func TestFrameFormatFullName(t *testing.T) {
+	var x X
+	tests := []struct {
+		Frame
+		format string
+		want   string
+	}{
+		{initpc, "%+n", `^github\.com/pkg/errors\.init(\.ializers)?$`},
+		{x.val(), "%+n", `^github\.com/pkg/errors\.X\.val$`},
+		{x.ptr(), "%+n", `^github\.com/pkg/errors\.\(\*X\)\.ptr$`},
+		{0, "%+n", `^unknown$`},
+		{initpc, "%d", `^\d+$`},
+	}
+
+	for i, tt := range tests {
+		testFormatRegexp(t, i, tt.Frame, tt.format, tt.want)
+	}
+}
+
+func TestStackTraceFromPCs(t *testing.T) {
+	var pcs [8]uintptr
+	n := runtime.Callers(1, pcs[:])
+	st := StackTraceFromPCs(pcs[:n])
+	if len(st) != n {
+		t.Fatalf("StackTraceFromPCs: got %d frames, want %d", len(st), n)
+	}
+	testFormatRegexp(t, 0, st[0], "%+v", "github.com/pkg/errors.TestStackTraceFromPCs\n\t.+/github.com/pkg/errors/stack_test.go:\\d+")
+	if got := FrameFromPC(pcs[0]); got != st[0] {
+		t.Errorf("FrameFromPC: got %v, want %v", got, st[0])
+	}
+	if got := StackTraceFromPCs(nil); len(got) != 0 {
+		t.Errorf("StackTraceFromPCs(nil): got %v, want no frames", got)
+	}
+}
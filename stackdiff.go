@@ -0,0 +1,71 @@
+package errors
+
+// StackDiff is the difference between two stack traces computed by
+// DiffStacks. Frames are compared by their Key, so stack traces of the same
+// call sites compare equal across runs and builds.
+type StackDiff struct {
+	// Removed holds the frames of the first stack trace that are not in
+	// the second, innermost first.
+	Removed []Frame
+
+	// Added holds the frames of the second stack trace that are not in
+	// the first, innermost first.
+	Added []Frame
+
+	// Common holds the longest sequence of frames found, in order, in
+	// both stack traces, innermost first. The frames are those of the
+	// first stack trace.
+	Common []Frame
+}
+
+// DiffStacks compares the stack traces a and b.
+func DiffStacks(a, b StackTrace) StackDiff {
+	ka, kb := frameKeys(a), frameKeys(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// ka[i:] and kb[j:].
+	lcs := make([][]int, len(ka)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(kb)+1)
+	}
+	for i := len(ka) - 1; i >= 0; i-- {
+		for j := len(kb) - 1; j >= 0; j-- {
+			switch {
+			case ka[i] == kb[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var d StackDiff
+	i, j := 0, 0
+	for i < len(ka) && j < len(kb) {
+		switch {
+		case ka[i] == kb[j]:
+			d.Common = append(d.Common, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			d.Removed = append(d.Removed, a[i])
+			i++
+		default:
+			d.Added = append(d.Added, b[j])
+			j++
+		}
+	}
+	d.Removed = append(d.Removed, a[i:]...)
+	d.Added = append(d.Added, b[j:]...)
+	return d
+}
+
+func frameKeys(st StackTrace) []string {
+	keys := make([]string, len(st))
+	for i, f := range st {
+		keys[i] = f.Key()
+	}
+	return keys
+}
@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffStacks(t *testing.T) {
+	var x X
+	f1, f2, f3, f4 := initpc, x.val(), x.ptr(), caller()
+
+	tests := []struct {
+		a, b StackTrace
+		want StackDiff
+	}{{
+		a:    nil,
+		b:    nil,
+		want: StackDiff{},
+	}, {
+		a:    StackTrace{f1, f2},
+		b:    StackTrace{f1, f2},
+		want: StackDiff{Common: []Frame{f1, f2}},
+	}, {
+		a:    StackTrace{f1, f2, f4},
+		b:    StackTrace{f3, f2, f4},
+		want: StackDiff{Removed: []Frame{f1}, Added: []Frame{f3}, Common: []Frame{f2, f4}},
+	}, {
+		a:    StackTrace{f1, f2},
+		b:    StackTrace{f3, f4},
+		want: StackDiff{Removed: []Frame{f1, f2}, Added: []Frame{f3, f4}},
+	}, {
+		a:    StackTrace{f1, f4},
+		b:    StackTrace{f1, f2, f3, f4},
+		want: StackDiff{Added: []Frame{f2, f3}, Common: []Frame{f1, f4}},
+	}}
+
+	for i, tt := range tests {
+		got := DiffStacks(tt.a, tt.b)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: DiffStacks(%v, %v):\n got %#v\nwant %#v", i+1, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Summary returns a one line summary of err, suitable for the title of an
+// alert or the status of a span where the whole message of a long chain
+// does not fit: the message of its outermost layer, its code, if any, and
+// the frame it originated at, if it has a stack trace, such as
+//
+//	load config [E1234] at main.loadConfig (config.go:42)
+//
+// The origin is the frame the innermost error with a stack trace in err's
+// chain was created at; see SameOrigin. Summary returns the empty string if
+// err is nil.
+func Summary(err error) string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(firstLine(Messages(err)[0]))
+	if code := CodeOf(err); code != "" {
+		b.WriteString(" [")
+		b.WriteString(code)
+		b.WriteString("]")
+	}
+	if f, ok := origin(err); ok && f.Name() != "unknown" {
+		name := f.Name()
+		b.WriteString(" at ")
+		b.WriteString(name[strings.LastIndex(name, "/")+1:])
+		b.WriteString(" (")
+		b.WriteString(path.Base(normalizePath(f.File())))
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(f.Line()))
+		b.WriteString(")")
+	}
+	return b.String()
+}
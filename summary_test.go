@@ -0,0 +1,32 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	if got := Summary(nil); got != "" {
+		t.Errorf("Summary(nil): got %q, want empty", got)
+	}
+
+	inner := New("inner")
+	f, _ := origin(inner)
+	at := fmt.Sprintf(" at errors.TestSummary (summary_test.go:%d)", f.Line())
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{io.EOF, "EOF"},
+		{WithMessage(io.EOF, "read"), "read"},
+		{WithCode(WithMessage(io.EOF, "read"), "E1"), "read [E1]"},
+		{inner, "inner" + at},
+		{WithCode(Wrap(Wrap(inner, "read"), "load\nconfig"), "E2"), "load [E2]" + at},
+	}
+	for i, tt := range tests {
+		if got := Summary(tt.err); got != tt.want {
+			t.Errorf("test %d: got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// Supervisor restarts a long-running function whenever it fails, whether
+// by returning an error or by panicking, reporting each failure with the
+// function set by SetReporter. The failures are annotated with the fields
+// "restarts", the number of times the function was restarted before it
+// failed, and "uptime", the time.Duration it ran for, as well as with
+// "supervisor", the Name of the Supervisor, if set.
+type Supervisor struct {
+	// Name identifies the supervised function in the failures reported.
+	Name string
+
+	// Backoff is the delay before restarting the function, 1s if zero.
+	Backoff time.Duration
+
+	// MaxRestarts is the number of times the function is restarted before
+	// the Supervisor gives up, unlimited if zero.
+	MaxRestarts int
+}
+
+// Go runs fn in a new goroutine, supervised by s, until it returns nil,
+// ctx is done or s gives up. An error returned once ctx is done, caused by
+// it, such as ctx.Err(), is a shutdown rather than a failure, and is not
+// reported. The returned channel is closed once supervision has stopped. Errors without a stack trace are annotated with
+// the stack trace of the point Go was called.
+func (s *Supervisor) Go(ctx context.Context, fn func(ctx context.Context) error) <-chan struct{} {
+	l := launchSite()
+	done := make(chan struct{})
+	backoff := s.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	name, max := s.Name, s.MaxRestarts
+	go func() {
+		defer close(done)
+		for restarts := 0; ; restarts++ {
+			start := time.Now()
+			err := l.run(func() error { return fn(ctx) })
+			if err == nil {
+				return
+			}
+			if ctx.Err() != nil && Is(err, ctx.Err()) {
+				// fn stopped because ctx is done: a shutdown, not a
+				// failure.
+				return
+			}
+			fields := []Field{Label("restarts", restarts), Label("uptime", time.Since(start))}
+			if name != "" {
+				fields = append(fields, Label("supervisor", name))
+			}
+			report(&withFields{err, fields})
+			if max > 0 && restarts == max {
+				return
+			}
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return done
+}
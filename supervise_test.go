@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSupervisor(t *testing.T) {
+	defer SetReporter(nil)
+
+	var reported []error
+	SetReporter(func(err error) { reported = append(reported, err) })
+	runs := 0
+	s := &Supervisor{Name: "worker", Backoff: time.Millisecond}
+	<-s.Go(context.Background(), func(context.Context) error {
+		runs++
+		switch runs {
+		case 1:
+			return io.EOF
+		case 2:
+			panic("boom")
+		}
+		return nil
+	})
+	if runs != 3 || len(reported) != 2 {
+		t.Fatalf("Supervisor: got %d runs and %d failures, want 3 and 2", runs, len(reported))
+	}
+	if !Is(reported[0], io.EOF) || !IsPanic(reported[1]) {
+		t.Errorf("Supervisor: reported %v", reported)
+	}
+	for i, err := range reported {
+		fields := Fields(err)
+		if len(fields) != 3 || fields[0] != Label("restarts", i) || fields[1].Key != "uptime" || fields[2] != Label("supervisor", "worker") {
+			t.Errorf("failure %d: got fields %v", i, fields)
+		}
+	}
+
+	reported = nil
+	s = &Supervisor{Backoff: time.Millisecond, MaxRestarts: 2}
+	<-s.Go(context.Background(), func(context.Context) error { return io.EOF })
+	if len(reported) != 3 {
+		t.Errorf("MaxRestarts: got %d failures, want 3", len(reported))
+	}
+
+	reported = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	s = &Supervisor{Backoff: time.Hour}
+	<-s.Go(ctx, func(context.Context) error {
+		cancel()
+		return io.EOF
+	})
+	if len(reported) != 1 {
+		t.Errorf("cancelled: got %d failures, want 1", len(reported))
+	}
+
+	reported = nil
+	ctx, cancel = context.WithCancel(context.Background())
+	s = &Supervisor{Backoff: time.Millisecond}
+	<-s.Go(ctx, func(ctx context.Context) error {
+		cancel()
+		return Wrap(ctx.Err(), "shutting down")
+	})
+	if len(reported) != 0 {
+		t.Errorf("shut down: got failures %v, want none", reported)
+	}
+}
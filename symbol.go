@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+)
+
+// symbol is the function name, file and line of a program counter, and
+// the entry address of its function.
+type symbol struct {
+	name  string
+	file  string
+	line  int
+	entry uintptr
+}
+
+var unknownSymbol = symbol{name: "unknown", file: "unknown"}
+
+// defaultSymbolCacheSize is the default number of entries in the symbol
+// cache.
+const defaultSymbolCacheSize = 4096
+
+// symbols caches the symbols of the most recently used program counters.
+var symbols = &symbolCache{size: defaultSymbolCacheSize}
+
+type symbolCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[uintptr]*list.Element // of *symbolEntry
+	lru     list.List                 // most recently used first
+}
+
+type symbolEntry struct {
+	pc uintptr
+	symbol
+}
+
+// SetSymbolCacheSize sets the number of program counters whose function
+// name, file and line are cached, so that formatting the same stack traces
+// over and over does not look the same symbols up every time. The cache
+// holds the most recently used entries; a size of 0 disables it, for
+// memory constrained environments. The default size is 4096.
+func SetSymbolCacheSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	symbols.mu.Lock()
+	defer symbols.mu.Unlock()
+	symbols.size = size
+	for symbols.lru.Len() > size {
+		symbols.evict()
+	}
+}
+
+// symbol returns the symbol of the program counter of f.
+func (f Frame) symbol() symbol {
+	pc := f.PC()
+	if s, ok := symbols.get(pc); ok {
+		return s
+	}
+	s := lookupSymbol(pc)
+	symbols.add(pc, s)
+	return s
+}
+
+// lookupSymbol looks the symbol of pc up in the runtime tables.
+func lookupSymbol(pc uintptr) symbol {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return unknownSymbol
+	}
+	file, line := fn.FileLine(pc)
+	return symbol{name: fn.Name(), file: file, line: line, entry: fn.Entry()}
+}
+
+func (c *symbolCache) get(pc uintptr) (symbol, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[pc]
+	if !ok {
+		return symbol{}, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*symbolEntry).symbol, true
+}
+
+func (c *symbolCache) add(pc uintptr, s symbol) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size == 0 {
+		return
+	}
+	if _, ok := c.entries[pc]; ok {
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[uintptr]*list.Element)
+	}
+	c.entries[pc] = c.lru.PushFront(&symbolEntry{pc, s})
+	for c.lru.Len() > c.size {
+		c.evict()
+	}
+}
+
+// evict removes the least recently used entry. c.mu must be held.
+func (c *symbolCache) evict() {
+	e := c.lru.Back()
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*symbolEntry).pc)
+}
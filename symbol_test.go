@@ -0,0 +1,40 @@
+package errors
+
+import "testing"
+
+func TestSymbolCache(t *testing.T) {
+	defer SetSymbolCacheSize(defaultSymbolCacheSize)
+
+	SetSymbolCacheSize(2)
+	var x X
+	frames := []Frame{initpc, x.val(), x.ptr()}
+	for _, f := range frames {
+		if got, want := f.symbol(), lookupSymbol(f.PC()); got != want {
+			t.Errorf("symbol(): got %+v, want %+v", got, want)
+		}
+	}
+	if n := symbols.lru.Len(); n != 2 {
+		t.Errorf("cache holds %d entries, want 2", n)
+	}
+	if _, ok := symbols.get(initpc.PC()); ok {
+		t.Errorf("least recently used entry %v was not evicted", initpc)
+	}
+	if s, ok := symbols.get(frames[2].PC()); !ok || s.name != "github.com/pkg/errors.(*X).ptr" {
+		t.Errorf("get(%v): got %+v, %v", frames[2], s, ok)
+	}
+
+	SetSymbolCacheSize(0)
+	if n := symbols.lru.Len(); n != 0 {
+		t.Errorf("disabled cache holds %d entries, want 0", n)
+	}
+	if got := initpc.Name(); got != "github.com/pkg/errors.init" {
+		t.Errorf("Name() without cache: got %q", got)
+	}
+	if symbols.lru.Len() != 0 {
+		t.Errorf("disabled cache was filled")
+	}
+
+	if got := Frame(0).symbol(); got != unknownSymbol {
+		t.Errorf("symbol() of unknown frame: got %+v, want %+v", got, unknownSymbol)
+	}
+}
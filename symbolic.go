@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SymbolicFrames selects the frames printed symbolically, as their
+// function and the offset of their program counter in it, such as
+// "github.com/org/repo/db.(*Conn).Query+0x1c", rather than as their
+// function, file and line; see SetSymbolicFrames.
+type SymbolicFrames int
+
+// The policies of symbolic frames.
+const (
+	// SymbolicNever prints every frame with its file and line.
+	SymbolicNever SymbolicFrames = iota
+
+	// SymbolicWithoutSource prints symbolically the frames whose source
+	// file is unknown or recorded as a relative path, as programs built
+	// with -trimpath record them.
+	SymbolicWithoutSource
+
+	// SymbolicAlways prints every frame symbolically.
+	SymbolicAlways
+)
+
+// SetSymbolicFrames sets which frames are printed symbolically by %+v and
+// Frame.MarshalText, as returned by Frame.Symbolic, so that the stack
+// traces of programs whose source paths are not available remain
+// meaningful: the offsets identify the call sites of a given build, and
+// can be resolved against its executable, with "go tool addr2line" for
+// example. Frames are printed with their file and line by default.
+func SetSymbolicFrames(mode SymbolicFrames) {
+	updateConfig(func(c *config) { c.symbolic = mode })
+}
+
+// Symbolic returns the symbolic form of this Frame: the name of its
+// function followed by the offset of the frame in it, such as
+// "github.com/pkg/errors.New+0x3a", as printed by the tracebacks of the
+// runtime.
+func (f Frame) Symbolic() string {
+	s := f.symbol()
+	if s.entry == 0 {
+		return s.name
+	}
+	return s.name + "+0x" + strconv.FormatUint(uint64(uintptr(f)-s.entry), 16)
+}
+
+// symbolic reports whether f is printed symbolically with c.
+func (f Frame) symbolic(c *config) bool {
+	switch c.symbolic {
+	case SymbolicAlways:
+		return true
+	case SymbolicWithoutSource:
+		file := f.File()
+		return file == "" || file == "?" || file == "unknown" || !strings.HasPrefix(normalizePath(file), "/")
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFrameSymbolic(t *testing.T) {
+	st, _ := stackTraceOf(New("x"))
+	f := st[0]
+	fn := runtime.FuncForPC(f.PC())
+	want := fmt.Sprintf("github.com/pkg/errors.TestFrameSymbolic+0x%x", uintptr(f)-fn.Entry())
+	if got := f.Symbolic(); got != want {
+		t.Errorf("Symbolic(): got %q, want %q", got, want)
+	}
+	if got := Frame(0).Symbolic(); got != "unknown" {
+		t.Errorf("Symbolic() of unknown frame: got %q, want %q", got, "unknown")
+	}
+}
+
+func TestSetSymbolicFrames(t *testing.T) {
+	defer SetSymbolicFrames(SymbolicNever)
+
+	err := New("x")
+	st, _ := stackTraceOf(err)
+	tests := []struct {
+		mode     SymbolicFrames
+		symbolic bool
+	}{
+		{SymbolicNever, false},
+		{SymbolicWithoutSource, false}, // the tests are not built with -trimpath
+		{SymbolicAlways, true},
+	}
+	for i, tt := range tests {
+		SetSymbolicFrames(tt.mode)
+		got := fmt.Sprintf("%+v", err)
+		if symbolic := strings.HasPrefix(got, "x\n"+st[0].Symbolic()+"\n"); symbolic != tt.symbolic {
+			t.Errorf("test %d: %%+v: got %q, want symbolic frames: %t", i+1, got, tt.symbolic)
+		}
+		text, _ := st[0].MarshalText()
+		if symbolic := string(text) == st[0].Symbolic(); symbolic != tt.symbolic {
+			t.Errorf("test %d: MarshalText: got %q, want symbolic frames: %t", i+1, text, tt.symbolic)
+		}
+	}
+
+	SetSymbolicFrames(SymbolicWithoutSource)
+	if !Frame(0).symbolic(loadConfig()) {
+		t.Errorf("frame without source file: got not symbolic, want symbolic")
+	}
+}
@@ -0,0 +1,43 @@
+package errors
+
+import "sync/atomic"
+
+// teeDropped counts the errors TeeChan dropped.
+var teeDropped uint64
+
+// TeeFunc calls fn with err, unless it is nil, and returns err unchanged, so
+// that an error can be both reported and returned in one expression:
+//
+//	return errors.TeeFunc(errors.Wrap(err, "decode"), log.Print)
+func TeeFunc(err error, fn func(error)) error {
+	if err != nil {
+		fn(err)
+	}
+	return err
+}
+
+// TeeChan sends err on ch, unless it is nil, and returns err unchanged, like
+// TeeFunc:
+//
+//	return errors.TeeChan(errors.Wrap(err, "decode"), reports)
+//
+// err is sent only if the send can proceed right away: errors are dropped
+// rather than blocking the caller when ch is full, and counted by
+// TeeDropped.
+func TeeChan(err error, ch chan<- error) error {
+	if err == nil {
+		return nil
+	}
+	select {
+	case ch <- err:
+	default:
+		atomic.AddUint64(&teeDropped, 1)
+	}
+	return err
+}
+
+// TeeDropped returns the number of errors TeeChan dropped because their
+// channel was full, since the process started.
+func TeeDropped() uint64 {
+	return atomic.LoadUint64(&teeDropped)
+}
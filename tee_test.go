@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"io"
+	"testing"
+)
+
+func TestTeeFunc(t *testing.T) {
+	var got []error
+	if err := TeeFunc(io.EOF, func(err error) { got = append(got, err) }); err != io.EOF {
+		t.Errorf("TeeFunc: got %v, want io.EOF", err)
+	}
+	if TeeFunc(nil, func(err error) { got = append(got, err) }) != nil {
+		t.Errorf("TeeFunc(nil): got non-nil error")
+	}
+	if len(got) != 1 || got[0] != io.EOF {
+		t.Errorf("TeeFunc: got %v, want [io.EOF]", got)
+	}
+}
+
+func TestTeeChan(t *testing.T) {
+	ch := make(chan error, 1)
+	dropped := TeeDropped()
+	if err := TeeChan(io.EOF, ch); err != io.EOF {
+		t.Errorf("TeeChan: got %v, want io.EOF", err)
+	}
+	if TeeChan(nil, ch) != nil {
+		t.Errorf("TeeChan(nil): got non-nil error")
+	}
+	TeeChan(io.ErrUnexpectedEOF, ch) // dropped, the channel is full
+	if err := <-ch; err != io.EOF {
+		t.Errorf("TeeChan: got %v, want io.EOF", err)
+	}
+	if got := TeeDropped() - dropped; got != 1 {
+		t.Errorf("TeeDropped: got %d more, want 1", got)
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+// Transform returns err's chain rebuilt by applying fn to each of its
+// layers, innermost first, at API boundaries for example, to redact
+// messages or attach codes systematically. fn is called with the layer
+// rebuilt around the transformed layers it wraps, which Unwrap returns, and
+// returns the error replacing it, or the layer itself to keep it:
+//
+//	err = errors.Transform(err, func(err error) error {
+//		if errors.Is(err, sql.ErrNoRows) {
+//			return ErrNotFound
+//		}
+//		return err
+//	})
+//
+// The annotations of this package, such as stack traces, fields and codes,
+// are not passed to fn but rebuilt around the transformed layers, so that
+// they are preserved; the errors of a layer that fn and the layers it wraps
+// leave unchanged are not copied. The errors joined by a *GroupError are
+// transformed each, while the errors joined by other errors with an
+// Unwrap() []error method are passed to fn as a whole. A wrapper of
+// another package whose cause changed is replaced by an error with the
+// same message, as returned by Messages, wrapping the transformed cause.
+// If err is nil, Transform returns nil. Chains longer than the depth
+// followed by Cause, such as cyclic ones, are only transformed up to that
+// depth; the layers below are kept as they are.
+func Transform(err error, fn func(error) error) error {
+	return transformWithin(err, fn, maxChainDepth)
+}
+
+// transformWithin is like Transform, following at most n errors down each
+// branch of err's chain.
+func transformWithin(err error, fn func(error) error, n int) error {
+	if err == nil || n == 0 {
+		return err
+	}
+	out := err
+	switch e := err.(type) {
+	case *GroupError:
+		errs := make([]error, len(e.Errors))
+		changed := false
+		for i, err := range e.Errors {
+			errs[i] = transformWithin(err, fn, n-1)
+			changed = changed || !same(errs[i], err)
+		}
+		if changed {
+			out = &GroupError{Errors: errs}
+		}
+	case interface{ Unwrap() []error }:
+	default:
+		if inner := Unwrap(err); inner != nil {
+			if t := transformWithin(inner, fn, n-1); !same(t, inner) {
+				out = rebuild(err, inner, t)
+			}
+		}
+	}
+	if isAnnotation(err) {
+		return out
+	}
+	return fn(out)
+}
+
+// rebuild returns a copy of err, which wraps inner, wrapping cause instead.
+func rebuild(err, inner, cause error) error {
+	if c, ok := rewrap(err, cause); ok {
+		return c
+	}
+	return &withMessage{cause: cause, msg: layerMessage(err, inner.Error())}
+}
+
+// rewrap returns a copy of err wrapping cause instead of the error it
+// wraps, if err is a wrapper of this package that can be copied.
+func rewrap(err, cause error) (error, bool) {
+	switch w := err.(type) {
+	case *withMessage:
+		return &withMessage{cause: cause, msg: w.msg, lazy: w.lazy, pc: w.pc}, true
+	case *withStack:
+		c := *w
+		c.error = cause
+		return &c, true
+	case formatted:
+		return formatted{cause}, true
+	case *truncated:
+		return &truncated{cause, w.n}, true
+	case *withGoroutineDump:
+		return &withGoroutineDump{cause, w.dump}, true
+	case *withCode:
+		return &withCode{cause, w.code}, true
+	case *withKind:
+		return &withKind{cause, w.kind}, true
+	case *withSuppressed:
+		return &withSuppressed{cause, w.suppressed}, true
+	case *withStage:
+		return &withStage{cause, w.name, w.index}, true
+	case *withRetry:
+		c := *w
+		c.error = cause
+		return &c, true
+	case *withFields:
+		return &withFields{cause, w.fields}, true
+	case *withDetached:
+		return &withDetached{cause, w.fields}, true
+	case *withReplacement:
+		return &withReplacement{cause, w.replacement}, true
+	case *withAffixes:
+		return affix(cause, w.prefix, w.suffix), true
+	case *withHandled:
+		return &withHandled{cause}, true
+	case *withStdlibFormat:
+		return &withStdlibFormat{cause}, true
+	case *withHint:
+		return &withHint{cause, w.hint}, true
+	case *withExitCode:
+		return &withExitCode{cause, w.code}, true
+	case *localized:
+		c := *w
+		c.cause = cause
+		return &c, true
+	default:
+		return nil, false
+	}
+}
+
+// same reports whether a and b are the same error. Errors that cannot be
+// compared, because they hold values of an uncomparable type, are not the
+// same.
+func same(a, b error) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return a == b
+}
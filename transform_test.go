@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	errNotFound := New("not found")
+	base := WithFields(os.ErrNotExist, Label("key", "a"))
+	err := Wrap(fmt.Errorf("lookup secret-42: %w", base), "get")
+	got := Transform(err, func(err error) error {
+		switch {
+		case err == os.ErrNotExist:
+			return errNotFound
+		case strings.Contains(err.Error(), "secret"):
+			return WithMessage(Unwrap(err), "lookup [redacted]")
+		}
+		return err
+	})
+	if want := "get: lookup [redacted]: not found"; got.Error() != want {
+		t.Errorf("Transform: got %q, want %q", got, want)
+	}
+	if !Is(got, errNotFound) || Is(got, os.ErrNotExist) {
+		t.Errorf("Transform: %v does not wrap the replacement only", got)
+	}
+	if fields := Fields(got); len(fields) != 1 || fields[0] != Label("key", "a") {
+		t.Errorf("Transform: got fields %v", fields)
+	}
+	want, _ := stackTraceOf(err)
+	if st, ok := stackTraceOf(got); !ok || st[0] != want[0] {
+		t.Errorf("Transform: got stack trace %v, want %v", st, want)
+	}
+	if Depth(got) != Depth(err) {
+		t.Errorf("Transform: got depth %d, want %d", Depth(got), Depth(err))
+	}
+
+	var visited []string
+	unchanged := Transform(err, func(err error) error {
+		visited = append(visited, err.Error())
+		return err
+	})
+	if unchanged != err {
+		t.Errorf("Transform: got a copy of a chain left unchanged")
+	}
+	if want := []string{"file does not exist", "lookup secret-42: file does not exist", "get: lookup secret-42: file does not exist"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("Transform: visited %q, want %q", visited, want)
+	}
+
+	group := &GroupError{Errors: []error{io.EOF, Wrap(os.ErrNotExist, "stat")}}
+	got = Transform(group, func(err error) error {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	})
+	if want := "unexpected EOF; stat: file does not exist"; got.Error() != want {
+		t.Errorf("Transform: got %q, want %q", got, want)
+	}
+	if Transform(nil, func(err error) error { return err }) != nil {
+		t.Errorf("Transform(nil): got non-nil error")
+	}
+}
+
+func TestTransformCyclic(t *testing.T) {
+	c := &cyclic{}
+	c.next = &cyclic{next: c}
+	err := Wrap(c, "x")
+	calls := 0
+	got := Transform(err, func(err error) error {
+		calls++
+		return err
+	})
+	if got != err {
+		t.Errorf("Transform: got %v, want the cyclic chain unchanged", got)
+	}
+	if calls == 0 || calls > maxChainDepth {
+		t.Errorf("Transform: called fn %d times, want at most %d", calls, maxChainDepth)
+	}
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// SetStackLimit limits the number of frames printed by %+v: stack traces
+// longer than 2*n frames are printed as their n innermost and n outermost
+// frames, separated by a line such as "… 57 frames elided". n <= 0, the
+// default, prints stack traces in full.
+func SetStackLimit(n int) {
+	updateConfig(func(c *config) { c.stackLimit = nonNegative(n) })
+}
+
+// writeElided writes the marker standing in for n elided frames.
+func writeElided(w io.Writer, n int) {
+	fmt.Fprintf(w, "\n… %d frames elided", n)
+}
+
+// TruncateStack returns an error wrapping err whose stack trace keeps only
+// the n innermost and n outermost frames of the stack trace of err, like
+// SetStackLimit does for every error. The frames in between are elided
+// from both its %+v output and its StackTrace.
+// If err is nil, has no stack trace, or n <= 0, TruncateStack returns err.
+func TruncateStack(err error, n int) error {
+	if err == nil || n <= 0 || !hasStack(err) {
+		return err
+	}
+	return &truncated{err, n}
+}
+
+type truncated struct {
+	error
+	n int
+}
+
+func (t *truncated) Cause() error { return t.error }
+
+func (t *truncated) Unwrap() error { return t.error }
+
+// StackTrace returns the truncated stack trace of the wrapped error.
+func (t *truncated) StackTrace() StackTrace {
+	st, _ := stackTraceOf(t.error)
+	if len(st) <= 2*t.n {
+		return st
+	}
+	out := make(StackTrace, 0, 2*t.n)
+	out = append(out, st[:t.n]...)
+	return append(out, st[len(st)-t.n:]...)
+}
+
+func (t *truncated) Format(s fmt.State, verb rune) { formatError(s, verb, t) }
+
+func (*truncated) own() {}
+
+func (t *truncated) writeStack(w io.Writer, o frameOptions) {
+	o.limit = t.n
+	writeStackOf(w, t.error, o)
+}
@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func deepErrors(depth int) error {
+	if depth == 0 {
+		return New("deep")
+	}
+	return deepErrors(depth - 1)
+}
+
+func TestTruncateStack(t *testing.T) {
+	if got := TruncateStack(nil, 2); got != nil {
+		t.Errorf("TruncateStack(nil, 2): got %#v, want nil", got)
+	}
+	bare := fmt.Errorf("bare")
+	if got := TruncateStack(bare, 2); got != bare {
+		t.Errorf("TruncateStack(bare, 2): got %#v, want it unchanged", got)
+	}
+	if !HasStack(Wrap(TruncateStack(bare, 2), "wrapped")) {
+		t.Errorf("Wrap(TruncateStack(bare, 2)): got no stack trace")
+	}
+
+	err := deepErrors(20)
+	st, _ := stackTraceOf(err)
+	trunc := TruncateStack(err, 2)
+	if got := trunc.Error(); got != "deep" {
+		t.Errorf("Error(): got %q, want %q", got, "deep")
+	}
+	if !Is(trunc, err) {
+		t.Errorf("Is(TruncateStack(err, 2), err): got false, want true")
+	}
+
+	tst, _ := stackTraceOf(trunc)
+	if len(tst) != 4 || tst[0] != st[0] || tst[3] != st[len(st)-1] {
+		t.Errorf("StackTrace(): got %v, want the 2 innermost and 2 outermost frames of %v", tst, st)
+	}
+
+	got := fmt.Sprintf("%+v", trunc)
+	want := fmt.Sprintf("\n… %d frames elided\n", len(st)-4)
+	if !strings.Contains(got, want) {
+		t.Errorf("%%+v: got %q, want it to contain %q", got, want)
+	}
+	if n := strings.Count(got, "\n\t"); n != 4 {
+		t.Errorf("%%+v: got %d frames, want 4", n)
+	}
+}
+
+func TestSetStackLimit(t *testing.T) {
+	defer SetStackLimit(0)
+
+	err := deepErrors(20)
+	st, _ := stackTraceOf(err)
+	SetStackLimit(3)
+	got := fmt.Sprintf("%+v", err)
+	if n := strings.Count(got, "\n\t"); n != 6 {
+		t.Errorf("%%+v: got %d frames, want 6", n)
+	}
+	if want := fmt.Sprintf("… %d frames elided", len(st)-6); !strings.Contains(got, want) {
+		t.Errorf("%%+v: got %q, want it to contain %q", got, want)
+	}
+
+	SetStackLimit(len(st))
+	if got := fmt.Sprintf("%+v", err); strings.Contains(got, "elided") {
+		t.Errorf("%%+v: got %q, want the full stack", got)
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import "reflect"
+
+// Walk calls fn for err and every error in its chain, in a deterministic
+// depth-first pre-order: fn is called for an error before the errors it
+// wraps, which are the error returned by its Unwrap() error method or, in
+// order, the errors returned by its Unwrap() []error method. depth is the
+// number of calls to Unwrap leading from err to the error visited, 0 for
+// err itself. If fn returns false, Walk does not visit the errors wrapped
+// by the error visited, but carries on with the rest of the chain.
+//
+// Walk is safe to call with chains containing cycles, which misbehaving
+// wrappers can create: an error wrapping, directly or not, an error it is
+// itself wrapped by is visited but the errors it wraps are not visited
+// again, and errors deeper than the package follows chains are not
+// visited. If err is nil, fn is not called.
+func Walk(err error, fn func(err error, depth int) bool) {
+	walk(err, 0, fn, make(map[error]bool))
+}
+
+// walk visits err at the given depth; path holds the comparable errors
+// wrapping err.
+func walk(err error, depth int, fn func(error, int) bool, path map[error]bool) {
+	if err == nil || depth > maxChainDepth {
+		return
+	}
+	if !fn(err, depth) {
+		return
+	}
+	comparable := reflect.TypeOf(err).Comparable()
+	if comparable {
+		if path[err] {
+			return
+		}
+		path[err] = true
+		defer delete(path, err)
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range u.Unwrap() {
+			walk(err, depth+1, fn, path)
+		}
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), depth+1, fn, path)
+	}
+}
+
+// Causes returns the errors err directly wraps: the errors returned by its
+// Unwrap() []error method, such as those of an error created by fmt.Errorf
+// with several %w verbs, or the error returned by its Unwrap() error
+// method, if not nil. Causes returns nil if err wraps no error.
+func Causes(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	case interface{ Unwrap() error }:
+		if err := u.Unwrap(); err != nil {
+			return []error{err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// cyclic is an error wrapping itself through next.
+type cyclic struct {
+	next error
+}
+
+func (c *cyclic) Error() string { return "cyclic" }
+
+func (c *cyclic) Unwrap() error { return c.next }
+
+func TestWalk(t *testing.T) {
+	err := WithMessage(&GroupError{Errors: []error{WithMessage(io.EOF, "read"), os.ErrNotExist}}, "load")
+	var got []string
+	Walk(err, func(err error, depth int) bool {
+		got = append(got, fmt.Sprintf("%d %v", depth, err))
+		return true
+	})
+	want := []string{"0 load: read: EOF; file does not exist", "1 read: EOF; file does not exist", "2 read: EOF", "3 EOF", "2 file does not exist"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk: got %q, want %q", got, want)
+	}
+
+	got = nil
+	Walk(err, func(err error, depth int) bool {
+		got = append(got, fmt.Sprintf("%d %v", depth, err))
+		return depth < 2
+	})
+	want = []string{"0 load: read: EOF; file does not exist", "1 read: EOF; file does not exist", "2 read: EOF", "2 file does not exist"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk: got %q, want %q when pruning", got, want)
+	}
+
+	c := &cyclic{}
+	c.next = &cyclic{next: c}
+	n := 0
+	Walk(c, func(error, int) bool {
+		n++
+		return true
+	})
+	if n != 3 {
+		t.Errorf("Walk: visited %d errors of a cycle of 2, want 3", n)
+	}
+
+	Walk(nil, func(error, int) bool {
+		t.Errorf("Walk(nil): fn called")
+		return true
+	})
+}
+
+// endless is an error wrapping a new endless error, making a chain that
+// never ends without being a cycle of comparable errors.
+type endless struct {
+	depth []int
+}
+
+func (e endless) Error() string { return "endless" }
+
+func (e endless) Unwrap() error { return endless{append(e.depth, 0)} }
+
+func TestCyclicChain(t *testing.T) {
+	c := &cyclic{}
+	c.next = &cyclic{next: c}
+	for _, err := range []error{c, endless{}} {
+		if got := Cause(err); got != ErrCyclicChain {
+			t.Errorf("Cause(%T): got %v, want ErrCyclicChain", err, got)
+		}
+		w := Wrap(err, "wrap")
+		if got, want := fmt.Sprintf("%v", w), "wrap: "+err.Error(); got != want {
+			t.Errorf("%%v: got %q, want %q", got, want)
+		}
+		if Cause(w) != w.(*withMessage).cause {
+			t.Errorf("Cause(Wrap(%T)): got %v, want its stack trace", err, Cause(w))
+		}
+		if got := Depth(err); got != maxChainDepth {
+			t.Errorf("Depth(%T): got %d, want %d", err, got, maxChainDepth)
+		}
+		if IsCode(err, "E1") || HasStack(err) {
+			t.Errorf("%T: got a code or a stack trace", err)
+		}
+		n := 0
+		Walk(err, func(error, int) bool {
+			n++
+			return true
+		})
+		if n > maxChainDepth+1 {
+			t.Errorf("Walk(%T): visited %d errors", err, n)
+		}
+	}
+}
+
+func TestCauses(t *testing.T) {
+	multi := fmt.Errorf("%w and %w", io.EOF, os.ErrNotExist)
+	tests := []struct {
+		err  error
+		want []error
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{fmt.Errorf("read: %w", io.EOF), []error{io.EOF}},
+		{multi, []error{io.EOF, os.ErrNotExist}},
+		{&GroupError{Errors: []error{io.EOF}}, []error{io.EOF}},
+	}
+	for i, tt := range tests {
+		if got := Causes(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Causes(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+	if got := Causes(Wrap(multi, "load")); len(got) != 1 || Unwrap(got[0]) != multi {
+		t.Errorf("Causes(Wrap): got %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+//go:build xerrors
+// +build xerrors
+
+package errors
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Building with the xerrors build tag implements the Formatter interface of
+// golang.org/x/xerrors on the errors of this package, so that they print
+// layer by layer, with the stack trace of each layer in its detail, through
+// the frameworks printing errors with xerrors.FormatError. It is optional
+// so that the package does not depend on golang.org/x/xerrors otherwise.
+
+// formatLayer prints the message of the layer of a chain starting with err,
+// and its stack trace if p asks for details, and returns the next layer.
+func formatLayer(err error, p xerrors.Printer) error {
+	var st stackWriter
+	var affixes []*withAffixes
+	e := err
+	for isAnnotation(e) {
+		if s, ok := e.(stackWriter); ok && st == nil {
+			st = s
+		}
+		if w, ok := e.(*withAffixes); ok {
+			affixes = append(affixes, w)
+		}
+		e = Unwrap(e)
+	}
+	var msg string
+	var next error
+	if w, ok := e.(*withMessage); ok {
+		msg = w.message()
+		next = w.cause
+		// The stack trace recorded by Wrap belongs to the layer of its
+		// message.
+		if ws, ok := w.wrapStack(); ok {
+			if st == nil {
+				st = ws
+			}
+			next = ws.error
+		}
+	} else {
+		msg = e.Error()
+	}
+	for i := len(affixes) - 1; i >= 0; i-- {
+		msg = affixes[i].prefix + msg + affixes[i].suffix
+	}
+	p.Print(msg)
+	if st != nil && p.Detail() {
+		var b strings.Builder
+		st.writeStack(&b, frameOptions{})
+		p.Print(strings.TrimPrefix(b.String(), "\n"))
+	}
+	return next
+}
+
+func (f formatted) FormatError(p xerrors.Printer) error { return formatLayer(f, p) }
+
+func (w *withStack) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withMessage) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (e *lazyError) FormatError(p xerrors.Printer) error { return formatLayer(e, p) }
+
+func (t *truncated) FormatError(p xerrors.Printer) error { return formatLayer(t, p) }
+
+func (w *withGoroutineDump) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withCode) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withKind) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withSuppressed) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withStage) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withRetry) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withFields) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withDetached) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withReplacement) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withAffixes) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withHandled) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withStdlibFormat) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withHint) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
+
+func (w *withExitCode) FormatError(p xerrors.Printer) error { return formatLayer(w, p) }
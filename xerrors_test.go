@@ -0,0 +1,60 @@
+//go:build xerrors
+// +build xerrors
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+// printer records what is printed through it, as the printers of
+// golang.org/x/xerrors do.
+type printer struct {
+	strings.Builder
+	detail bool
+}
+
+func (p *printer) Print(args ...interface{})                 { fmt.Fprint(p, args...) }
+func (p *printer) Printf(format string, args ...interface{}) { fmt.Fprintf(p, format, args...) }
+func (p *printer) Detail() bool                              { return p.detail }
+
+func TestFormatErrorXErrors(t *testing.T) {
+	inner := New("inner")
+	tests := []struct {
+		err  error
+		want []string // the message of each layer
+	}{
+		{inner, []string{"inner"}},
+		{Wrap(inner, "outer"), []string{"outer", "inner"}},
+		{WithCode(Wrap(io.EOF, "read"), "E1"), []string{"read", "EOF"}},
+		{Prefix(Wrap(inner, "outer"), "op"), []string{"op: outer", "inner"}},
+		{WithMessage(WithMessage(io.EOF, "b"), "a"), []string{"a", "b", "EOF"}},
+	}
+	for i, tt := range tests {
+		var got []string
+		for err := tt.err; err != nil; {
+			f, ok := err.(xerrors.Formatter)
+			if !ok {
+				got = append(got, err.Error())
+				break
+			}
+			var p printer
+			err = f.FormatError(&p)
+			got = append(got, p.String())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("test %d: got layers %q, want %q", i+1, got, tt.want)
+		}
+	}
+
+	p := printer{detail: true}
+	Wrap(io.EOF, "read").(xerrors.Formatter).FormatError(&p)
+	if got := p.String(); !strings.HasPrefix(got, "read") || !strings.Contains(got, "TestFormatErrorXErrors") {
+		t.Errorf("detail: got %q, want the message followed by its stack trace", got)
+	}
+}